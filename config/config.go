@@ -20,6 +20,15 @@ type Config struct {
 		Password  string `default:"password"`
 		Migration bool   `default:"false"`
 	}
+	Extension struct {
+		MasterGenerator bool   `yaml:"master_generator" default:"false"`
+		CorsEnabled     bool   `yaml:"cors_enabled" default:"false"`
+		SecurityEnabled bool   `yaml:"security_enabled" default:"false"`
+		FoodImportDir   string `yaml:"food_import_dir" default:""`
+	}
+	Nutrient struct {
+		Micronutrients []string `yaml:"micronutrients"`
+	}
 }
 
 const (
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// ActivityController is a controller for managing activities.
+type ActivityController interface {
+	GetActivity(c echo.Context) error
+	GetActivityList(c echo.Context) error
+	CreateActivity(c echo.Context) error
+	UpdateActivity(c echo.Context) error
+	DeleteActivity(c echo.Context) error
+	GetActivityTypeList(c echo.Context) error
+}
+
+type activityController struct {
+	container container.Container
+	service   service.ActivityService
+}
+
+// NewActivityController is constructor.
+func NewActivityController(container container.Container) ActivityController {
+	return &activityController{container: container, service: service.NewActivityService(container)}
+}
+
+// GetActivity returns one record matched activity's id.
+// @Summary Get an activity
+// @Description Get an activity of the logged-in user
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Param activity_id path int true "Activity ID"
+// @Success 200 {object} model.Activity "Success to fetch data."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities/{activity_id} [get]
+func (controller *activityController) GetActivity(c echo.Context) error {
+	activity, err := controller.service.FindByID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, activity)
+}
+
+// GetActivityList returns the list of all activities of the logged-in user.
+// @Summary Get an activity list
+// @Description Get the list of all activities of the logged-in user, the newest first
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Activity "Success to fetch a activity list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities [get]
+func (controller *activityController) GetActivityList(c echo.Context) error {
+	activities, err := controller.service.FindAllActivities()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, activities)
+}
+
+// CreateActivity create a new activity by http post.
+// @Summary Create a new activity
+// @Description Create a new activity
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Param data body dto.ActivityDto true "a new activity data for creating. calories_burned is estimated by MET if omitted."
+// @Success 200 {object} model.Activity "Success to create a new activity."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities [post]
+func (controller *activityController) CreateActivity(c echo.Context) error {
+	dto := dto.NewActivityDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	activity, result := controller.service.CreateActivity(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, activity)
+}
+
+// UpdateActivity update the existing activity by http put.
+// @Summary Update the existing activity
+// @Description Update the existing activity
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Param activity_id path int true "Activity ID"
+// @Param data body dto.ActivityDto true "the activity data for updating"
+// @Success 200 {object} model.Activity "Success to update the existing activity."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities/{activity_id} [put]
+func (controller *activityController) UpdateActivity(c echo.Context) error {
+	dto := dto.NewActivityDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	activity, result := controller.service.UpdateActivity(dto, c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, activity)
+}
+
+// DeleteActivity deletes the existing activity by http delete.
+// @Summary Delete the existing activity
+// @Description Delete the existing activity
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Param activity_id path int true "Activity ID"
+// @Success 200 {object} model.Activity "Success to delete the existing activity."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities/{activity_id} [delete]
+func (controller *activityController) DeleteActivity(c echo.Context) error {
+	activity, result := controller.service.DeleteActivity(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, activity)
+}
+
+// GetActivityTypeList returns the list of all activity types.
+// @Summary Get an activity type list
+// @Description Get the list of all activity types with their MET
+// @Tags Activities
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.ActivityType "Success to fetch an activity type list."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /activities/types [get]
+func (controller *activityController) GetActivityTypeList(c echo.Context) error {
+	return c.JSON(http.StatusOK, controller.service.FindAllActivityTypes())
+}
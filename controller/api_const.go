@@ -7,8 +7,86 @@ const (
 	APIMeals = API + "/Meals"
 	// APIMealsID represents the API to get meals data using id.
 	APIMealsID = APIMeals + "/:id"
+	// APIMealsCopy represents the API to copy the meals of a day to another day.
+	APIMealsCopy = APIMeals + "/copy"
+	// APIMealsRecalculate represents the API to recalculate the nutrients of a meal with the current foods.
+	APIMealsRecalculate = APIMealsID + "/recalculate"
+	// APIMealsBatch represents the API to create, update and delete meals in a batch.
+	APIMealsBatch = APIMeals + "/batch"
 	// APIFoods represents the group of food  API.
 	APIFoods = API + "/food"
+	// APIFoodsID represents the API to get food data using id.
+	APIFoodsID = APIFoods + "/:id"
+	// APIFoodsImport represents the API to import foods from a file.
+	APIFoodsImport = APIFoods + "/import"
+	// APIFoodsBarcode represents the API to get the food matched a barcode.
+	APIFoodsBarcode = APIFoods + "/barcode/:code"
+	// APIFoodsTags represents the API to update the allergen and diet tags of a food.
+	APIFoodsTags = APIFoods + "/:id/tags"
+	// APIFoodsSizes represents the API to update the serving size, the piece size and the density of a food.
+	APIFoodsSizes = APIFoods + "/:id/sizes"
+	// APIFoodsMerge represents the API to merge a duplicate food into a food.
+	APIFoodsMerge = APIFoods + "/:id/merge"
+	// APIFoodsFrequent represents the API to get the foods logged frequently.
+	APIFoodsFrequent = APIFoods + "/frequent"
+	// APISummary represents the group of summary API.
+	APISummary = API + "/summary"
+	// APISummaryDaily represents the API to get the daily summary of the logged-in user.
+	APISummaryDaily = APISummary + "/daily"
+	// APIGoals represents the group of goals API.
+	APIGoals = API + "/goals"
+	// APIGoalsProgress represents the API to get the progress toward the goal.
+	APIGoalsProgress = APIGoals + "/progress"
+	// APIReports represents the group of reports API.
+	APIReports = API + "/reports"
+	// APIReportsTrends represents the API to get the nutrition trend.
+	APIReportsTrends = APIReports + "/trends"
+	// APIWeights represents the group of weights API.
+	APIWeights = API + "/weights"
+	// APIWeightsID represents the API to get weight data using id.
+	APIWeightsID = APIWeights + "/:id"
+	// APIActivities represents the group of activities API.
+	APIActivities = API + "/activities"
+	// APIActivitiesID represents the API to get activity data using id.
+	APIActivitiesID = APIActivities + "/:id"
+	// APIActivityTypes represents the API to get the list of activity types.
+	APIActivityTypes = APIActivities + "/types"
+	// APIHydration represents the group of hydration API.
+	APIHydration = API + "/hydration"
+	// APIHydrationID represents the API to delete hydration data using id.
+	APIHydrationID = APIHydration + "/:id"
+	// APIHydrationDaily represents the API to get the daily hydration.
+	APIHydrationDaily = APIHydration + "/daily"
+	// APIRecipes represents the group of recipes API.
+	APIRecipes = API + "/recipes"
+	// APIRecipesID represents the API to get recipe data using id.
+	APIRecipesID = APIRecipes + "/:id"
+	// APITemplates represents the group of meal templates API.
+	APITemplates = API + "/templates"
+	// APITemplatesID represents the API to delete meal template data using id.
+	APITemplatesID = APITemplates + "/:id"
+	// APITemplatesLog represents the API to log the meals of a meal template.
+	APITemplatesLog = APITemplatesID + "/log"
+	// APITemplatesFavorite represents the API to mark and unmark a meal template as a favorite.
+	APITemplatesFavorite = APITemplatesID + "/favorite"
+	// APIPlans represents the group of meal plans API.
+	APIPlans = API + "/plans"
+	// APIPlansID represents the API to delete planned meal data using id.
+	APIPlansID = APIPlans + "/:id"
+	// APIPlansEat represents the API to mark a planned meal as eaten.
+	APIPlansEat = APIPlansID + "/eat"
+	// APIPlansSkip represents the API to mark a planned meal as skipped.
+	APIPlansSkip = APIPlansID + "/skip"
+	// APIShoppingLists represents the group of shopping lists API.
+	APIShoppingLists = API + "/shopping-lists"
+	// APIShoppingListsID represents the API to get shopping list data using id.
+	APIShoppingListsID = APIShoppingLists + "/:id"
+	// APIShoppingListsItem represents the API to check an item of a shopping list.
+	APIShoppingListsItem = APIShoppingListsID + "/items/:item_id"
+	// APIProfile represents the API to get and update the body profile of the logged-in user.
+	APIProfile = API + "/profile"
+	// APIRestrictions represents the API to get and update the allergens and diets of the logged-in user.
+	APIRestrictions = API + "/restrictions"
 )
 
 const (
@@ -1,37 +1,302 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
 	"github.com/ybkuroki/go-webapp-sample/service"
 )
 
 // FoodController is a controller for managing Food data.
 type FoodController interface {
+	GetFood(c echo.Context) error
 	GetFoodList(c echo.Context) error
+	CreateFood(c echo.Context) error
+	UpdateFood(c echo.Context) error
+	DeleteFood(c echo.Context) error
+	GetFoodByBarcode(c echo.Context) error
+	GetFrequentFoods(c echo.Context) error
+	UpdateFoodTags(c echo.Context) error
+	UpdateFoodSizes(c echo.Context) error
+	MergeFoods(c echo.Context) error
+	ImportFoods(c echo.Context) error
 }
 
-type FoodController struct {
+type foodController struct {
 	container container.Container
 	service   service.FoodService
 }
 
 // NewFoodController is constructor.
 func NewFoodController(container container.Container) FoodController {
-	return &FoodController{container: container, service: service.NewFoodService(container)}
+	return &foodController{container: container, service: service.NewFoodService(container)}
 }
 
-// GetFoodList returns the list of all foods.
+// GetFood returns one record matched food's id.
+// @Summary Get a Food
+// @Description Get a Food of the global catalog or a private Food of the logged-in user
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID"
+// @Success 200 {object} model.Food "Success to fetch data."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /food/{id} [get]
+func (controller *foodController) GetFood(c echo.Context) error {
+	food, err := controller.service.FindFood(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// GetFoodList returns the list of all foods with their nutrients per 100 g.
+// The list has the foods of the global catalog and the private foods of the logged-in user.
+// If the query or the filter is given, it returns the page object of the foods similar to the query in order of relevance.
 // @Summary Get a Food list
-// @Description Get a Food list
+// @Description Get a Food list with the calories, macronutrients, micronutrients per 100 g and tags, or search Foods tolerating typos
 // @Tags Food
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} model.Food "Success to fetch a Food list."
+// @Param q query string false "Keyword. The keyword shorter than 3 characters matches the Foods which start with it."
+// @Param exclude query string false "Comma separated allergens. The Foods which contain any of them are excluded."
+// @Param diet query string false "Comma separated diets. Only the Foods suitable for all of them are returned."
+// @Param page query int false "Page number"
+// @Param size query int false "Item size per page"
+// @Success 200 {array} model.Food "Success to fetch a Food list. Returns model.Page if the keyword is given."
+// @Failure 400 {string} message "Failed to fetch data."
 // @Failure 401 {string} false "Failed to the authentication."
-// @Router /foods [get]
-func (controller *FoodController) GetFoodList(c echo.Context) error {
-	return c.JSON(http.StatusOK, controller.service.FindAllFood())
+// @Router /food [get]
+func (controller *foodController) GetFoodList(c echo.Context) error {
+	if c.QueryParam("q") == "" && c.QueryParam("exclude") == "" && c.QueryParam("diet") == "" {
+		return c.JSON(http.StatusOK, controller.service.FindAllFoods())
+	}
+
+	page, err := controller.service.SearchFoods(c.QueryParam("q"), c.QueryParam("exclude"), c.QueryParam("diet"),
+		c.QueryParam("page"), c.QueryParam("size"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// CreateFood create a new Food by http post. It is the private Food of the logged-in user
+// unless the administrator adds it to the global catalog.
+// @Summary Create a new Food
+// @Description Create a new private Food, or a Food of the global catalog by the administrator
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param data body dto.FoodDto true "a new Food data for creating"
+// @Success 200 {object} model.Food "Success to create a new Food."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /food [post]
+func (controller *foodController) CreateFood(c echo.Context) error {
+	dto := dto.NewFoodDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	food, result := controller.service.CreateFood(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// UpdateFood update the existing Food by http put. A user can update only the own Foods
+// and the administrator can update the Foods of the global catalog.
+// @Summary Update the existing Food
+// @Description Update the name, the nutrients, the sizes and the tags of the existing Food
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID"
+// @Param data body dto.FoodDto true "the Food data for updating"
+// @Success 200 {object} model.Food "Success to update the existing Food."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /food/{id} [put]
+func (controller *foodController) UpdateFood(c echo.Context) error {
+	dto := dto.NewFoodDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	food, result := controller.service.UpdateFood(c.Param("id"), dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// DeleteFood deletes the existing Food by http delete. A user can delete only the own Foods
+// and the administrator can delete the Foods of the global catalog.
+// @Summary Delete the existing Food
+// @Description Delete the existing Food. It is soft-deleted if the Meals, the Recipes or the plans use it, so that it is hidden from the catalog but they are not changed.
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID"
+// @Success 200 {object} model.Food "Success to delete the existing Food. deleted_at is set if it is soft-deleted."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /food/{id} [delete]
+func (controller *foodController) DeleteFood(c echo.Context) error {
+	food, result := controller.service.DeleteFood(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// GetFoodByBarcode returns the packaged Food matched given barcode.
+// An unknown barcode is returned as the not found error handled by ErrorController.
+// @Summary Get a Food by barcode
+// @Description Get the packaged Food matched given EAN-13 or UPC-A barcode
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param code path string true "EAN-13 or UPC-A barcode"
+// @Success 200 {object} model.Food "Success to fetch data."
+// @Failure 400 {object} controller.APIError "The barcode is invalid."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 404 {object} controller.APIError "No food has the barcode."
+// @Router /food/barcode/{code} [get]
+func (controller *foodController) GetFoodByBarcode(c echo.Context) error {
+	food, err := controller.service.FindFoodByBarcode(c.Param("code"))
+	switch {
+	case errors.Is(err, service.ErrInvalidBarcode):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrFoodNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "No food has the barcode "+c.Param("code")+".")
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// GetFrequentFoods returns the Foods which the logged-in user has logged most frequently.
+// @Summary Get the frequent Foods
+// @Description Get the Foods which the logged-in user has logged most frequently with the portion logged last time
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Number of the Foods. 10 if omitted."
+// @Param days query int false "Count only the Meals in the last days. The whole history if omitted."
+// @Success 200 {array} model.FrequentFood "Success to fetch the frequent Foods."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /food/frequent [get]
+func (controller *foodController) GetFrequentFoods(c echo.Context) error {
+	foods, err := controller.service.FindFrequentFoods(c.QueryParam("limit"), c.QueryParam("days"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, foods)
+}
+
+// UpdateFoodTags replaces the allergen and the diet tags of the Food by http put. Only the administrator can update.
+// @Summary Update the tags of a Food
+// @Description Replace the allergens which the Food contains and the diets which the Food is suitable for
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID"
+// @Param data body dto.FoodTagDto true "the allergens and the diets"
+// @Success 200 {object} model.Food "Success to update the tags."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 403 {boolean} bool "The current user is not the administrator. Returns false."
+// @Router /food/{id}/tags [put]
+func (controller *foodController) UpdateFoodTags(c echo.Context) error {
+	dto := dto.NewFoodTagDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	food, result := controller.service.UpdateFoodTags(c.Param("id"), dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// UpdateFoodSizes updates the sizes of the Food which convert the portions to grams by http put.
+// Only the administrator can update.
+// @Summary Update the sizes of a Food
+// @Description Update the grams of a serving and a piece and the grams per milliliter, which convert the portions in servings, pieces and the units of volume to grams
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID"
+// @Param data body dto.FoodSizeDto true "the sizes. 0 means that the Food has no such size."
+// @Success 200 {object} model.Food "Success to update the sizes."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 403 {boolean} bool "The current user is not the administrator. Returns false."
+// @Router /food/{id}/sizes [put]
+func (controller *foodController) UpdateFoodSizes(c echo.Context) error {
+	dto := dto.NewFoodSizeDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	food, result := controller.service.UpdateFoodSizes(c.Param("id"), dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// MergeFoods merges a duplicate Food into the Food by http post. Only the administrator can merge.
+// @Summary Merge a duplicate Food
+// @Description Re-point all Meals, Recipes, plans, templates, hydration entries and shopping lists from the duplicate to the Food and delete the duplicate. The nutrients of the logged Meals are not changed.
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Food ID of the survivor"
+// @Param data body dto.FoodMergeDto true "the ID of the duplicate Food"
+// @Success 200 {object} model.Food "Success to merge the Foods. Returns the survivor."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 403 {boolean} bool "The current user is not the administrator. Returns false."
+// @Router /food/{id}/merge [post]
+func (controller *foodController) MergeFoods(c echo.Context) error {
+	dto := dto.NewFoodMergeDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	food, result := controller.service.MergeFoods(c.Param("id"), dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, food)
+}
+
+// ImportFoods imports Foods from a file in the import directory by http post. Only the administrator can import.
+// @Summary Import Foods
+// @Description Import Foods from a CSV file or a JSON file of USDA FoodData Central in the import directory
+// @Tags Food
+// @Accept  json
+// @Produce  json
+// @Param data body dto.FoodImportDto true "the file name and the format"
+// @Success 200 {object} model.ImportResult "Success to import. Returns the numbers of inserted, updated and skipped rows."
+// @Failure 400 {string} message "Failed to import."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 403 {boolean} bool "The current user is not the administrator. Returns false."
+// @Router /food/import [post]
+func (controller *foodController) ImportFoods(c echo.Context) error {
+	dto := dto.NewFoodImportDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	if errors := dto.Validate(); errors != nil {
+		return c.JSON(http.StatusBadRequest, errors)
+	}
+
+	result, err := controller.service.ImportFoodsFromDirectory(dto.File(), dto.Format())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, result)
 }
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// GoalController is a controller for managing Goals.
+type GoalController interface {
+	GetGoalList(c echo.Context) error
+	UpdateGoal(c echo.Context) error
+	GetProgress(c echo.Context) error
+}
+
+type goalController struct {
+	container container.Container
+	service   service.GoalService
+}
+
+// NewGoalController is constructor.
+func NewGoalController(container container.Container) GoalController {
+	return &goalController{container: container, service: service.NewGoalService(container)}
+}
+
+// GetGoalList returns the history of the Goals of the logged-in user.
+// @Summary Get a Goal list
+// @Description Get the history of the Goals of the logged-in user, the newest first
+// @Tags Goals
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Goal "Success to fetch a Goal list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /goals [get]
+func (controller *goalController) GetGoalList(c echo.Context) error {
+	goals, err := controller.service.FindGoals()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, goals)
+}
+
+// UpdateGoal registers a new Goal by http put.
+// @Summary Update the Goal
+// @Description Register a new Goal. The previous Goals are kept as the history.
+// @Tags Goals
+// @Accept  json
+// @Produce  json
+// @Param data body dto.GoalDto true "a new Goal data"
+// @Success 200 {object} model.Goal "Success to update the Goal."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /goals [put]
+func (controller *goalController) UpdateGoal(c echo.Context) error {
+	dto := dto.NewGoalDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	goal, result := controller.service.UpdateGoal(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, goal)
+}
+
+// GetProgress returns the progress of the logged-in user toward the Goal in a day.
+// @Summary Get the progress toward the Goal
+// @Description Compare the Meals of a day with the Goal which applies to the day and report the remaining budget
+// @Tags Goals
+// @Accept  json
+// @Produce  json
+// @Param date query string false "Date (YYYY-MM-DD). Today if omitted."
+// @Success 200 {object} model.GoalProgress "Success to fetch the progress."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /goals/progress [get]
+func (controller *goalController) GetProgress(c echo.Context) error {
+	progress, err := controller.service.FindProgress(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, progress)
+}
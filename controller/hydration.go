@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// HydrationController is a controller for managing the hydration log.
+type HydrationController interface {
+	GetHydrationList(c echo.Context) error
+	GetDailyHydration(c echo.Context) error
+	CreateHydration(c echo.Context) error
+	DeleteHydration(c echo.Context) error
+}
+
+type hydrationController struct {
+	container container.Container
+	service   service.HydrationService
+}
+
+// NewHydrationController is constructor.
+func NewHydrationController(container container.Container) HydrationController {
+	return &hydrationController{container: container, service: service.NewHydrationService(container)}
+}
+
+// GetHydrationList returns the hydration log of the logged-in user.
+// @Summary Get a hydration list
+// @Description Get the hydration log of the logged-in user, the newest first
+// @Tags Hydration
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param size query int false "Item size per page"
+// @Success 200 {object} model.Page "Success to fetch a hydration list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /hydration [get]
+func (controller *hydrationController) GetHydrationList(c echo.Context) error {
+	page, err := controller.service.FindHydrationByPage(c.QueryParam("page"), c.QueryParam("size"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// GetDailyHydration returns the total volume and the target of the logged-in user in a day.
+// @Summary Get a daily hydration
+// @Description Get the total volume, the daily target derived from the weight and the calories of the beverages
+// @Tags Hydration
+// @Accept  json
+// @Produce  json
+// @Param date query string false "Date (YYYY-MM-DD). Today if omitted."
+// @Success 200 {object} model.Hydration "Success to fetch a daily hydration."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /hydration/daily [get]
+func (controller *hydrationController) GetDailyHydration(c echo.Context) error {
+	hydration, err := controller.service.FindDailyHydration(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, hydration)
+}
+
+// CreateHydration create a new hydration entry by http post.
+// @Summary Create a new hydration entry
+// @Description Create a new hydration entry
+// @Tags Hydration
+// @Accept  json
+// @Produce  json
+// @Param data body dto.HydrationDto true "a new hydration data for creating"
+// @Success 200 {object} model.HydrationEntry "Success to create a new hydration entry."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /hydration [post]
+func (controller *hydrationController) CreateHydration(c echo.Context) error {
+	dto := dto.NewHydrationDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	entry, result := controller.service.CreateHydration(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+// DeleteHydration deletes the existing hydration entry by http delete.
+// @Summary Delete the existing hydration entry
+// @Description Delete the existing hydration entry
+// @Tags Hydration
+// @Accept  json
+// @Produce  json
+// @Param hydration_id path int true "Hydration ID"
+// @Success 200 {object} model.HydrationEntry "Success to delete the existing hydration entry."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /hydration/{hydration_id} [delete]
+func (controller *hydrationController) DeleteHydration(c echo.Context) error {
+	entry, result := controller.service.DeleteHydration(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, entry)
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -14,6 +16,12 @@ type MealController interface {
 	GetMeal(c echo.Context) error
 	GetMealList(c echo.Context) error
 	CreateMeal(c echo.Context) error
+	CopyMeals(c echo.Context) error
+	RecalculateMeal(c echo.Context) error
+	BatchMeals(c echo.Context) error
+	UpdateMeal(c echo.Context) error
+	PatchMeal(c echo.Context) error
+	DeleteMeal(c echo.Context) error
 }
 
 type MealController struct {
@@ -36,34 +44,46 @@ func NewMealController(container container.Container) MealController {
 // @Success 200 {object} model.Meal "Success to fetch data."
 // @Failure 400 {string} message "Failed to fetch data."
 // @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Failure 404 {string} message "No meal of the logged-in user has the ID."
 // @Router /Meals/{Meal_id} [get]
 func (controller *MealController) GetMeal(c echo.Context) error {
 	Meal, err := controller.service.FindByID(c.Param("id"))
-	if err != nil {
+	switch {
+	case errors.Is(err, service.ErrMealNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "No meal of the logged-in user has the ID "+c.Param("id")+".")
+	case err != nil:
 		return c.JSON(http.StatusBadRequest, err.Error())
 	}
 	return c.JSON(http.StatusOK, Meal)
 }
 
-// GetMealList returns the list of matched Meals by searching.
+// GetMealList returns the list of the Meals of the logged-in user matched the filters in the given order.
 // @Summary Get a Meal list
-// @Description Get the list of matched Meals by searching
+// @Description Get the list of the Meals of the logged-in user matched the filters in the given order
 // @Tags Meals
 // @Accept  json
 // @Produce  json
-// @Param query query string false "Keyword"
+// @Param query query string false "Keyword of the name"
+// @Param from query string false "First day of meal_at (YYYY-MM-DD) in the time zone of the user"
+// @Param to query string false "Last day of meal_at (YYYY-MM-DD) in the time zone of the user"
+// @Param food_id query int false "Food ID which the Meals have"
+// @Param meal_type query string false "Meal type (breakfast, lunch, dinner or snack)"
+// @Param min_calories query number false "Minimum calories"
+// @Param max_calories query number false "Maximum calories"
+// @Param sort query string false "Fields to sort by separated by commas such as meal_at,-calories. Prefix - sorts in descending order. The newest first if omitted."
+// @Param cursor query string false "Cursor of the page, which is next or prev of the previous response. An empty cursor fetches the first page. It cannot be used with sort and page."
 // @Param page query int false "Page number"
 // @Param size query int false "Item size per page"
 // @Success 200 {object} model.Page "Success to fetch a Meal list."
-// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 400 {object} map[string]string "Failed to fetch data. Returns the messages of the unknown or invalid filters and sort fields."
 // @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
 // @Router /Meals [get]
 func (controller *MealController) GetMealList(c echo.Context) error {
-	Meal, err := controller.service.FindMealsByTitle(c.QueryParam("query"), c.QueryParam("page"), c.QueryParam("size"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	Meals, result := controller.service.FindMeals(dto.NewMealFilterDto(c.QueryParams()))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
 	}
-	return c.JSON(http.StatusOK, Meal)
+	return c.JSON(http.StatusOK, Meals)
 }
 
 // CreateMeal create a new Meal by http post.
@@ -73,8 +93,8 @@ func (controller *MealController) GetMealList(c echo.Context) error {
 // @Accept  json
 // @Produce  json
 // @Param data body dto.MealDto true "a new Meal data for creating"
-// @Success 200 {object} model.Meal "Success to create a new Meal."
-// @Failure 400 {string} message "Failed to the registration."
+// @Success 200 {object} model.Meal "Success to create a new Meal. The conflicts with the dietary restrictions are returned as the warnings."
+// @Failure 400 {string} message "Failed to the registration, or the Meal conflicts with the restrictions of the user who rejects such Meals."
 // @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
 // @Router /Meals [post]
 func (controller *MealController) CreateMeal(c echo.Context) error {
@@ -88,3 +108,139 @@ func (controller *MealController) CreateMeal(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, Meal)
 }
+
+// CopyMeals duplicates the Meals of a day to another day by http post.
+// @Summary Copy the Meals of a day
+// @Description Duplicate all Meals of the logged-in user on a day to another day at the same times
+// @Tags Meals
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Date to copy from (YYYY-MM-DD). The day before to if omitted."
+// @Param to query string false "Date to copy to (YYYY-MM-DD). Today if omitted."
+// @Success 200 {array} model.Meal "Success to copy the Meals."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/copy [post]
+func (controller *MealController) CopyMeals(c echo.Context) error {
+	Meals, result := controller.service.CopyMeals(c.QueryParam("from"), c.QueryParam("to"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, Meals)
+}
+
+// BatchMeals applies the create, update and delete operations on the Meals in a batch by http post.
+// @Summary Apply operations on the Meals in a batch
+// @Description Create, update and delete up to 100 Meals of the logged-in user in a transaction. In the atomic mode, none of the operations is applied if any of them fails. In the best_effort mode, the operations which succeed are applied and the others are skipped.
+// @Tags Meals
+// @Accept  json
+// @Produce  json
+// @Param data body dto.MealBatchDto true "the mode and the operations"
+// @Success 200 {object} model.MealBatchResult "Success to apply all operations."
+// @Success 207 {object} model.MealBatchResult "Some operations failed in the best_effort mode. The status and the errors of each operation are returned by its index."
+// @Failure 400 {object} model.MealBatchResult "Some operations failed in the atomic mode and none of them is applied, or the batch itself is invalid."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/batch [post]
+func (controller *MealController) BatchMeals(c echo.Context) error {
+	dto := dto.NewMealBatchDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	Meals, result := controller.service.BatchMeals(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	switch {
+	case !Meals.HasFailures():
+		return c.JSON(http.StatusOK, Meals)
+	case Meals.IsAtomic():
+		return c.JSON(http.StatusBadRequest, Meals)
+	}
+	return c.JSON(http.StatusMultiStatus, Meals)
+}
+
+// RecalculateMeal replaces the snapshots of the nutrients of a Meal with the current nutrients of the foods by http post.
+// @Summary Recalculate the nutrients of a Meal
+// @Description Replace the nutrients of a Meal of the logged-in user, which are snapshotted when it is logged, with the current nutrients of the foods
+// @Tags Meals
+// @Accept  json
+// @Produce  json
+// @Param Meal_id path int true "Meal ID"
+// @Success 200 {object} model.Meal "Success to recalculate the Meal."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/{Meal_id}/recalculate [post]
+func (controller *MealController) RecalculateMeal(c echo.Context) error {
+	Meal, result := controller.service.RecalculateMeal(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, Meal)
+}
+
+// UpdateMeal replaces the existing Meal by http put.
+// @Summary Update the existing Meal
+// @Description Replace the name, the time, the meal type and the items of the existing Meal of the logged-in user
+// @Tags Meals
+// @Accept  json
+// @Produce  json
+// @Param Meal_id path int true "Meal ID"
+// @Param data body dto.MealDto true "the Meal data for updating"
+// @Success 200 {object} model.Meal "Success to update the existing Meal. The conflicts with the dietary restrictions are returned as the warnings."
+// @Failure 400 {string} message "Failed to the update, or the Meal conflicts with the restrictions of the user who rejects such Meals."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/{Meal_id} [put]
+func (controller *MealController) UpdateMeal(c echo.Context) error {
+	dto := dto.NewMealDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	Meal, result := controller.service.UpdateMeal(dto, c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, Meal)
+}
+
+// PatchMeal updates the existing Meal partially by http patch with JSON Merge Patch.
+// @Summary Update the existing Meal partially
+// @Description Apply JSON Merge Patch (RFC 7386) to the existing Meal of the logged-in user. The items are kept unless the patch has the items or the recipes.
+// @Tags Meals
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param Meal_id path int true "Meal ID"
+// @Param data body dto.MealDto true "the members of the Meal data to update. null removes the member."
+// @Success 200 {object} model.Meal "Success to update the existing Meal."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/{Meal_id} [patch]
+func (controller *MealController) PatchMeal(c echo.Context) error {
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Failed to the update"})
+	}
+	Meal, result := controller.service.PatchMeal(patch, c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, Meal)
+}
+
+// DeleteMeal deletes the existing Meal by http delete.
+// @Summary Delete the existing Meal
+// @Description Delete the existing Meal of the logged-in user and its items
+// @Tags Meals
+// @Accept  json
+// @Produce  json
+// @Param Meal_id path int true "Meal ID"
+// @Success 200 {object} model.Meal "Success to delete the existing Meal."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /Meals/{Meal_id} [delete]
+func (controller *MealController) DeleteMeal(c echo.Context) error {
+	Meal, result := controller.service.DeleteMeal(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, Meal)
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// MealPlanController is a controller for planning meals.
+type MealPlanController interface {
+	GetCalendar(c echo.Context) error
+	CreatePlan(c echo.Context) error
+	EatPlan(c echo.Context) error
+	SkipPlan(c echo.Context) error
+	DeletePlan(c echo.Context) error
+}
+
+type mealPlanController struct {
+	container container.Container
+	service   service.MealPlanService
+}
+
+// NewMealPlanController is constructor.
+func NewMealPlanController(container container.Container) MealPlanController {
+	return &mealPlanController{container: container, service: service.NewMealPlanService(container)}
+}
+
+// GetCalendar returns the planned and the logged Meals of the logged-in user for each day.
+// @Summary Get the meal plan calendar
+// @Description Get the planned and the logged Meals side by side with the calorie delta for each day
+// @Tags Plans
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start date (YYYY-MM-DD). Today if omitted."
+// @Param to query string false "End date (YYYY-MM-DD). 7 days from the start if omitted."
+// @Success 200 {array} model.MealPlanDay "Success to fetch the calendar."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /plans [get]
+func (controller *mealPlanController) GetCalendar(c echo.Context) error {
+	calendar, err := controller.service.FindCalendar(c.QueryParam("from"), c.QueryParam("to"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, calendar)
+}
+
+// CreatePlan schedules a new Meal by http post.
+// @Summary Create a new planned Meal
+// @Description Schedule a Meal for a future date
+// @Tags Plans
+// @Accept  json
+// @Produce  json
+// @Param data body dto.PlannedMealDto true "a new planned Meal data for creating"
+// @Success 200 {object} model.PlannedMeal "Success to create a new planned Meal."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /plans [post]
+func (controller *mealPlanController) CreatePlan(c echo.Context) error {
+	dto := dto.NewPlannedMealDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	plan, result := controller.service.CreatePlan(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// EatPlan logs the planned Meal as a Meal by http post.
+// @Summary Mark a planned Meal as eaten
+// @Description Log a planned Meal as a Meal at the planned time and mark it as eaten
+// @Tags Plans
+// @Accept  json
+// @Produce  json
+// @Param plan_id path int true "Planned Meal ID"
+// @Success 200 {object} model.Meal "Success to log the Meal."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /plans/{plan_id}/eat [post]
+func (controller *mealPlanController) EatPlan(c echo.Context) error {
+	meal, result := controller.service.EatPlan(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, meal)
+}
+
+// SkipPlan marks the planned Meal as skipped by http post.
+// @Summary Mark a planned Meal as skipped
+// @Description Mark a planned Meal as skipped
+// @Tags Plans
+// @Accept  json
+// @Produce  json
+// @Param plan_id path int true "Planned Meal ID"
+// @Success 200 {object} model.PlannedMeal "Success to update the planned Meal."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /plans/{plan_id}/skip [post]
+func (controller *mealPlanController) SkipPlan(c echo.Context) error {
+	plan, result := controller.service.SkipPlan(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
+
+// DeletePlan deletes the existing planned Meal by http delete.
+// @Summary Delete the existing planned Meal
+// @Description Delete the existing planned Meal. The Meal logged from it is not changed.
+// @Tags Plans
+// @Accept  json
+// @Produce  json
+// @Param plan_id path int true "Planned Meal ID"
+// @Success 200 {object} model.PlannedMeal "Success to delete the existing planned Meal."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /plans/{plan_id} [delete]
+func (controller *mealPlanController) DeletePlan(c echo.Context) error {
+	plan, result := controller.service.DeletePlan(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, plan)
+}
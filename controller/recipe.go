@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// RecipeController is a controller for managing Recipes.
+type RecipeController interface {
+	GetRecipe(c echo.Context) error
+	GetRecipeList(c echo.Context) error
+	CreateRecipe(c echo.Context) error
+	UpdateRecipe(c echo.Context) error
+}
+
+type recipeController struct {
+	container container.Container
+	service   service.RecipeService
+}
+
+// NewRecipeController is constructor.
+func NewRecipeController(container container.Container) RecipeController {
+	return &recipeController{container: container, service: service.NewRecipeService(container)}
+}
+
+// GetRecipe returns one record matched Recipe's id.
+// @Summary Get a Recipe
+// @Description Get a Recipe with the nutrients per serving, optionally scaled to given servings
+// @Tags Recipes
+// @Accept  json
+// @Produce  json
+// @Param recipe_id path int true "Recipe ID"
+// @Param version query int false "Version. The current version if omitted."
+// @Param servings query number false "Servings to scale the Recipe to"
+// @Success 200 {object} model.Recipe "Success to fetch data."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /recipes/{recipe_id} [get]
+func (controller *recipeController) GetRecipe(c echo.Context) error {
+	recipe, err := controller.service.FindByID(c.Param("id"), c.QueryParam("version"), c.QueryParam("servings"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, recipe)
+}
+
+// GetRecipeList returns the list of all Recipes of the logged-in user.
+// @Summary Get a Recipe list
+// @Description Get the current versions of all Recipes of the logged-in user
+// @Tags Recipes
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.Recipe "Success to fetch a Recipe list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /recipes [get]
+func (controller *recipeController) GetRecipeList(c echo.Context) error {
+	recipes, err := controller.service.FindAllRecipes()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, recipes)
+}
+
+// CreateRecipe create a new Recipe by http post.
+// @Summary Create a new Recipe
+// @Description Create a new Recipe
+// @Tags Recipes
+// @Accept  json
+// @Produce  json
+// @Param data body dto.RecipeDto true "a new Recipe data for creating"
+// @Success 200 {object} model.Recipe "Success to create a new Recipe."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /recipes [post]
+func (controller *recipeController) CreateRecipe(c echo.Context) error {
+	dto := dto.NewRecipeDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	recipe, result := controller.service.CreateRecipe(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, recipe)
+}
+
+// UpdateRecipe adds a new version of the existing Recipe by http put.
+// @Summary Update the existing Recipe
+// @Description Add a new version of the existing Recipe. The Meals logged from the former versions are not changed.
+// @Tags Recipes
+// @Accept  json
+// @Produce  json
+// @Param recipe_id path int true "Recipe ID"
+// @Param data body dto.RecipeDto true "the Recipe data for updating"
+// @Success 200 {object} model.Recipe "Success to update the existing Recipe."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /recipes/{recipe_id} [put]
+func (controller *recipeController) UpdateRecipe(c echo.Context) error {
+	dto := dto.NewRecipeDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	recipe, result := controller.service.UpdateRecipe(dto, c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, recipe)
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// ReportController is a controller for reporting the nutrition.
+type ReportController interface {
+	GetTrends(c echo.Context) error
+}
+
+type reportController struct {
+	container container.Container
+	service   service.ReportService
+}
+
+// NewReportController is constructor.
+func NewReportController(container container.Container) ReportController {
+	return &reportController{container: container, service: service.NewReportService(container)}
+}
+
+// GetTrends returns the nutrition trend of the logged-in user.
+// @Summary Get a nutrition trend report
+// @Description Get the calorie and macronutrient totals, averages, min/max days and the adherence to the Goals by day, week or month
+// @Tags Reports
+// @Accept  json
+// @Produce  json
+// @Param from query string false "First date (YYYY-MM-DD). 30 days before to if omitted."
+// @Param to query string false "Last date (YYYY-MM-DD). Today if omitted."
+// @Param granularity query string false "day, week or month. day if omitted."
+// @Success 200 {object} model.TrendReport "Success to fetch a trend report."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /reports/trends [get]
+func (controller *reportController) GetTrends(c echo.Context) error {
+	report, err := controller.service.FindTrends(c.QueryParam("from"), c.QueryParam("to"), c.QueryParam("granularity"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+const (
+	// exportText represents the shopping list exported as plain text.
+	exportText = "text"
+	// exportCSV represents the shopping list exported as CSV.
+	exportCSV = "csv"
+)
+
+// ShoppingListController is a controller for managing shopping lists.
+type ShoppingListController interface {
+	GetShoppingList(c echo.Context) error
+	GetShoppingListList(c echo.Context) error
+	CreateShoppingList(c echo.Context) error
+	CheckItem(c echo.Context) error
+	DeleteShoppingList(c echo.Context) error
+}
+
+type shoppingListController struct {
+	container container.Container
+	service   service.ShoppingListService
+}
+
+// NewShoppingListController is constructor.
+func NewShoppingListController(container container.Container) ShoppingListController {
+	return &shoppingListController{container: container, service: service.NewShoppingListService(container)}
+}
+
+// GetShoppingList returns one shopping list matched its id, as JSON, plain text or CSV.
+// @Summary Get a shopping list
+// @Description Get a shopping list with the items grouped by category, optionally exported as plain text or CSV
+// @Tags ShoppingLists
+// @Accept  json
+// @Produce  json,plain,text/csv
+// @Param list_id path int true "Shopping list ID"
+// @Param format query string false "Export format: text or csv. JSON if omitted."
+// @Success 200 {object} model.ShoppingList "Success to fetch data."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /shopping-lists/{list_id} [get]
+func (controller *shoppingListController) GetShoppingList(c echo.Context) error {
+	list, err := controller.service.FindByID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	switch c.QueryParam("format") {
+	case exportText:
+		return c.String(http.StatusOK, list.Text())
+	case exportCSV:
+		body, err := list.CSV()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\"shopping-list-"+c.Param("id")+".csv\"")
+		return c.Blob(http.StatusOK, "text/csv; charset=utf-8", body)
+	case "":
+		return c.JSON(http.StatusOK, list)
+	}
+	return c.JSON(http.StatusBadRequest, "format must be text or csv")
+}
+
+// GetShoppingListList returns the list of all shopping lists of the logged-in user.
+// @Summary Get a shopping list list
+// @Description Get all shopping lists of the logged-in user without the items, the newest first
+// @Tags ShoppingLists
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.ShoppingList "Success to fetch a shopping list list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /shopping-lists [get]
+func (controller *shoppingListController) GetShoppingListList(c echo.Context) error {
+	lists, err := controller.service.FindAllShoppingLists()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, lists)
+}
+
+// CreateShoppingList generates a new shopping list by http post.
+// @Summary Create a new shopping list
+// @Description Generate a shopping list from the planned Meals in a date range and the Recipes to cook
+// @Tags ShoppingLists
+// @Accept  json
+// @Produce  json
+// @Param data body dto.ShoppingListDto true "the name, the date range and the Recipes"
+// @Success 200 {object} model.ShoppingList "Success to create a new shopping list."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /shopping-lists [post]
+func (controller *shoppingListController) CreateShoppingList(c echo.Context) error {
+	dto := dto.NewShoppingListDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	list, result := controller.service.CreateShoppingList(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// CheckItem marks or unmarks an item of the shopping list as bought by http put.
+// @Summary Check an item of a shopping list
+// @Description Mark or unmark an item of a shopping list as bought
+// @Tags ShoppingLists
+// @Accept  json
+// @Produce  json
+// @Param list_id path int true "Shopping list ID"
+// @Param item_id path int true "Item ID"
+// @Param data body dto.ShoppingListItemDto true "whether the item is bought"
+// @Success 200 {object} model.ShoppingListItem "Success to update the item."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /shopping-lists/{list_id}/items/{item_id} [put]
+func (controller *shoppingListController) CheckItem(c echo.Context) error {
+	dto := dto.NewShoppingListItemDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	item, result := controller.service.CheckItem(dto, c.Param("id"), c.Param("item_id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+// DeleteShoppingList deletes the existing shopping list by http delete.
+// @Summary Delete the existing shopping list
+// @Description Delete the existing shopping list
+// @Tags ShoppingLists
+// @Accept  json
+// @Produce  json
+// @Param list_id path int true "Shopping list ID"
+// @Success 200 {object} model.ShoppingList "Success to delete the existing shopping list."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /shopping-lists/{list_id} [delete]
+func (controller *shoppingListController) DeleteShoppingList(c echo.Context) error {
+	list, result := controller.service.DeleteShoppingList(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// SummaryController is a controller for summarizing Meals.
+type SummaryController interface {
+	GetDailySummary(c echo.Context) error
+}
+
+type summaryController struct {
+	container container.Container
+	service   service.SummaryService
+}
+
+// NewSummaryController is constructor.
+func NewSummaryController(container container.Container) SummaryController {
+	return &summaryController{container: container, service: service.NewSummaryService(container)}
+}
+
+// GetDailySummary returns the calories of the Meals which the logged-in user has taken in a day.
+// @Summary Get a daily summary
+// @Description Get the total calories, the number of Meals and the calories of each Meal in a day
+// @Tags Summary
+// @Accept  json
+// @Produce  json
+// @Param date query string false "Date (YYYY-MM-DD). Today if omitted."
+// @Success 200 {object} model.DailySummary "Success to fetch a daily summary."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /summary/daily [get]
+func (controller *summaryController) GetDailySummary(c echo.Context) error {
+	summary, err := controller.service.FindDailySummary(c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// MealTemplateController is a controller for managing meal templates.
+type MealTemplateController interface {
+	GetTemplateList(c echo.Context) error
+	CreateTemplate(c echo.Context) error
+	LogTemplate(c echo.Context) error
+	AddFavorite(c echo.Context) error
+	RemoveFavorite(c echo.Context) error
+	DeleteTemplate(c echo.Context) error
+}
+
+type mealTemplateController struct {
+	container container.Container
+	service   service.MealTemplateService
+}
+
+// NewMealTemplateController is constructor.
+func NewMealTemplateController(container container.Container) MealTemplateController {
+	return &mealTemplateController{container: container, service: service.NewMealTemplateService(container)}
+}
+
+// GetTemplateList returns the list of all meal templates of the logged-in user.
+// @Summary Get a meal template list
+// @Description Get the list of all meal templates of the logged-in user, the favorites first
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.MealTemplate "Success to fetch a meal template list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates [get]
+func (controller *mealTemplateController) GetTemplateList(c echo.Context) error {
+	templates, err := controller.service.FindAllTemplates()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, templates)
+}
+
+// CreateTemplate saves a meal or all meals of a day as a new template by http post.
+// @Summary Create a new meal template
+// @Description Save a meal, or all meals of a day, as a named template
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Param data body dto.MealTemplateDto true "the name of the template and the meal or the date to save"
+// @Success 200 {object} model.MealTemplate "Success to create a new meal template."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates [post]
+func (controller *mealTemplateController) CreateTemplate(c echo.Context) error {
+	dto := dto.NewMealTemplateDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	template, result := controller.service.CreateTemplate(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// LogTemplate logs the meals of the template again by http post.
+// @Summary Log the meals of a meal template
+// @Description Log the meals of a meal template on a given day at the saved times
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Param template_id path int true "Template ID"
+// @Param date query string false "Date (YYYY-MM-DD). Today if omitted."
+// @Success 200 {array} model.Meal "Success to log the meals."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates/{template_id}/log [post]
+func (controller *mealTemplateController) LogTemplate(c echo.Context) error {
+	meals, result := controller.service.LogTemplate(c.Param("id"), c.QueryParam("date"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, meals)
+}
+
+// AddFavorite marks the template as a favorite by http post.
+// @Summary Add a meal template to the favorites
+// @Description Mark a meal template as a favorite
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Param template_id path int true "Template ID"
+// @Success 200 {object} model.MealTemplate "Success to update the meal template."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates/{template_id}/favorite [post]
+func (controller *mealTemplateController) AddFavorite(c echo.Context) error {
+	template, result := controller.service.SetFavorite(c.Param("id"), true)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// RemoveFavorite unmarks the template as a favorite by http delete.
+// @Summary Remove a meal template from the favorites
+// @Description Unmark a meal template as a favorite
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Param template_id path int true "Template ID"
+// @Success 200 {object} model.MealTemplate "Success to update the meal template."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates/{template_id}/favorite [delete]
+func (controller *mealTemplateController) RemoveFavorite(c echo.Context) error {
+	template, result := controller.service.SetFavorite(c.Param("id"), false)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate deletes the existing template by http delete.
+// @Summary Delete the existing meal template
+// @Description Delete the existing meal template. The meals logged from it are not changed.
+// @Tags Templates
+// @Accept  json
+// @Produce  json
+// @Param template_id path int true "Template ID"
+// @Success 200 {object} model.MealTemplate "Success to delete the existing meal template."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /templates/{template_id} [delete]
+func (controller *mealTemplateController) DeleteTemplate(c echo.Context) error {
+	template, result := controller.service.DeleteTemplate(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, template)
+}
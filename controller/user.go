@@ -16,6 +16,10 @@ type UserController interface {
 	GetLoginUser(c echo.Context) error
 	Login(c echo.Context) error
 	Logout(c echo.Context) error
+	GetProfile(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	GetRestrictions(c echo.Context) error
+	UpdateRestrictions(c echo.Context) error
 }
 
 type UserController struct {
@@ -106,3 +110,85 @@ func (controller *UserController) Logout(c echo.Context) error {
 	_ = sess.Delete()
 	return c.NoContent(http.StatusOK)
 }
+
+// GetProfile returns the body profile of the logged-in user.
+// @Summary Get the body profile of logged-in user.
+// @Description Get the height, birth date, sex and activity level of logged-in user with BMI, BMR and TDEE.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Profile "Success to fetch the profile. energy is null if the weight or the profile is not registered."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "The current user haven't logged-in yet. Returns false."
+// @Router /profile [get]
+func (controller *UserController) GetProfile(c echo.Context) error {
+	profile, err := controller.service.FindProfile()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile updates the body profile and the time zone of the logged-in user by http put.
+// @Summary Update the body profile of logged-in user.
+// @Description Update the height, birth date, sex, activity level and IANA time zone of logged-in user. The time zone is kept if it is omitted.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param data body dto.ProfileDto true "the profile data for updating"
+// @Success 200 {object} model.Profile "Success to update the profile."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "The current user haven't logged-in yet. Returns false."
+// @Router /profile [put]
+func (controller *UserController) UpdateProfile(c echo.Context) error {
+	dto := dto.NewProfileDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	profile, result := controller.service.UpdateProfile(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// GetRestrictions returns the allergens and the diets of the logged-in user.
+// @Summary Get the dietary restrictions of logged-in user.
+// @Description Get the allergens to avoid, the diets to follow and whether the conflicting meals are warned or rejected.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} model.Restrictions "Success to fetch the restrictions."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "The current user haven't logged-in yet. Returns false."
+// @Router /restrictions [get]
+func (controller *UserController) GetRestrictions(c echo.Context) error {
+	restrictions, err := controller.service.FindRestrictions()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, restrictions)
+}
+
+// UpdateRestrictions replaces the allergens and the diets of the logged-in user by http put.
+// @Summary Update the dietary restrictions of logged-in user.
+// @Description Replace the allergens to avoid and the diets to follow. The mode warn logs the conflicting meals with the warnings and reject refuses them.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param data body dto.RestrictionDto true "the restrictions for updating"
+// @Success 200 {object} model.Restrictions "Success to update the restrictions."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "The current user haven't logged-in yet. Returns false."
+// @Router /restrictions [put]
+func (controller *UserController) UpdateRestrictions(c echo.Context) error {
+	dto := dto.NewRestrictionDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	restrictions, result := controller.service.UpdateRestrictions(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, restrictions)
+}
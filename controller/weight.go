@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/service"
+)
+
+// WeightController is a controller for managing body weights.
+type WeightController interface {
+	GetWeight(c echo.Context) error
+	GetWeightList(c echo.Context) error
+	CreateWeight(c echo.Context) error
+	UpdateWeight(c echo.Context) error
+	DeleteWeight(c echo.Context) error
+}
+
+type weightController struct {
+	container container.Container
+	service   service.WeightService
+}
+
+// NewWeightController is constructor.
+func NewWeightController(container container.Container) WeightController {
+	return &weightController{container: container, service: service.NewWeightService(container)}
+}
+
+// GetWeight returns one record matched weight's id.
+// @Summary Get a weight
+// @Description Get a weight of the logged-in user
+// @Tags Weights
+// @Accept  json
+// @Produce  json
+// @Param weight_id path int true "Weight ID"
+// @Success 200 {object} model.WeightEntry "Success to fetch data."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /weights/{weight_id} [get]
+func (controller *weightController) GetWeight(c echo.Context) error {
+	weight, err := controller.service.FindByID(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, weight)
+}
+
+// GetWeightList returns the list of all weights of the logged-in user.
+// @Summary Get a weight list
+// @Description Get the list of all weights of the logged-in user, the newest first
+// @Tags Weights
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} model.WeightEntry "Success to fetch a weight list."
+// @Failure 400 {string} message "Failed to fetch data."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /weights [get]
+func (controller *weightController) GetWeightList(c echo.Context) error {
+	weights, err := controller.service.FindAllWeights()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	return c.JSON(http.StatusOK, weights)
+}
+
+// CreateWeight create a new weight by http post.
+// @Summary Create a new weight
+// @Description Create a new weight
+// @Tags Weights
+// @Accept  json
+// @Produce  json
+// @Param data body dto.WeightDto true "a new weight data for creating"
+// @Success 200 {object} model.WeightEntry "Success to create a new weight."
+// @Failure 400 {string} message "Failed to the registration."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /weights [post]
+func (controller *weightController) CreateWeight(c echo.Context) error {
+	dto := dto.NewWeightDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	weight, result := controller.service.CreateWeight(dto)
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, weight)
+}
+
+// UpdateWeight update the existing weight by http put.
+// @Summary Update the existing weight
+// @Description Update the existing weight
+// @Tags Weights
+// @Accept  json
+// @Produce  json
+// @Param weight_id path int true "Weight ID"
+// @Param data body dto.WeightDto true "the weight data for updating"
+// @Success 200 {object} model.WeightEntry "Success to update the existing weight."
+// @Failure 400 {string} message "Failed to the update."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /weights/{weight_id} [put]
+func (controller *weightController) UpdateWeight(c echo.Context) error {
+	dto := dto.NewWeightDto()
+	if err := c.Bind(dto); err != nil {
+		return c.JSON(http.StatusBadRequest, dto)
+	}
+	weight, result := controller.service.UpdateWeight(dto, c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, weight)
+}
+
+// DeleteWeight deletes the existing weight by http delete.
+// @Summary Delete the existing weight
+// @Description Delete the existing weight
+// @Tags Weights
+// @Accept  json
+// @Produce  json
+// @Param weight_id path int true "Weight ID"
+// @Success 200 {object} model.WeightEntry "Success to delete the existing weight."
+// @Failure 400 {string} message "Failed to the delete."
+// @Failure 401 {boolean} bool "Failed to the authentication. Returns false."
+// @Router /weights/{weight_id} [delete]
+func (controller *weightController) DeleteWeight(c echo.Context) error {
+	weight, result := controller.service.DeleteWeight(c.Param("id"))
+	if result != nil {
+		return c.JSON(http.StatusBadRequest, result)
+	}
+	return c.JSON(http.StatusOK, weight)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"embed"
+	"flag"
 
 	"github.com/labstack/echo/v4"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/ybkuroki/go-webapp-sample/migration"
 	"github.com/ybkuroki/go-webapp-sample/repository"
 	"github.com/ybkuroki/go-webapp-sample/router"
+	"github.com/ybkuroki/go-webapp-sample/service"
 	"github.com/ybkuroki/go-webapp-sample/session"
 )
 
@@ -24,6 +26,9 @@ var zapYamlFile embed.FS
 //go:embed public/*
 var staticFile embed.FS
 
+var importFile = flag.String("import", "", "Import foods from a CSV file or a JSON file of USDA FoodData Central and exit.")
+var importFormat = flag.String("format", "", "The format of the import file: csv or usda. It is detected by the extension if empty.")
+
 // @license.name MIT
 // @license.url https://opensource.org/licenses/mit-license.php
 
@@ -33,16 +38,30 @@ func main() {
 	e := echo.New()
 
 	conf, env := config.Load(yamlFile)
+	if !flag.Parsed() {
+		flag.Parse()
+	}
 	logger := logger.InitLogger(env, zapYamlFile)
 	logger.GetZapLogger().Infof("Loaded this configuration : application." + env + ".yml")
 
-	rep := repository.NewBookRepository(logger, conf)
+	rep := repository.NewMealRepository(logger, conf)
 	sess := session.NewSession()
 	container := container.NewContainer(rep, sess, conf, logger, env)
 
 	migration.CreateDatabase(container)
 	migration.InitMasterData(container)
 
+	if *importFile != "" {
+		result, err := service.NewFoodService(container).ImportFoods(*importFile, *importFormat)
+		if err != nil {
+			logger.GetZapLogger().Errorf(err.Error())
+		} else {
+			logger.GetZapLogger().Infof(result.Summary())
+		}
+		rep.Close()
+		return
+	}
+
 	router.Init(e, container)
 	middleware.InitLoggerMiddleware(e, container)
 	middleware.InitSessionMiddleware(e, container)
@@ -125,6 +125,19 @@ func AuthenticationMiddleware(container container.Container) echo.MiddlewareFunc
 	}
 }
 
+// AdminMiddleware is the middleware which allows only the administrator to access the routes.
+// The other users get 403 Forbidden.
+func AdminMiddleware(container container.Container) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if user := container.GetSession().GetUser(); user == nil || !user.IsAdmin() {
+				return c.JSON(http.StatusForbidden, false)
+			}
+			return next(c)
+		}
+	}
+}
+
 // hasAuthorization judges whether the user has the right to access the path.
 func hasAuthorization(c echo.Context, container container.Container) bool {
 	currentPath := c.Path()
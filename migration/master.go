@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+// InitMasterData creates the master data used in this application.
+func InitMasterData(container container.Container) {
+	if container.GetConfig().Extension.MasterGenerator {
+		rep := container.GetRepository()
+
+		u := model.NewUserWithPlainPassword("test", "test", model.AuthorityAdmin)
+		_, _ = u.Create(rep)
+
+		// The amounts are per 100 g. Sodium and micronutrients are in mg, the others are in g.
+		// The purchase size is the grams of the purchase unit.
+		// The serving size and the piece size are in grams and the density is in grams per milliliter.
+		// The tags are the allergens which the food contains and the diets which the food is suitable for.
+		f := model.NewFood("Rice", model.NewNutrients(168, 2.5, 37.1, 0.3, 0.3, 0, 1,
+			micronutrients(container, map[string]float64{"vitamin_c": 0, "calcium": 3, "iron": 0.1, "potassium": 29})))
+		_, _ = f.SetPurchase("Grains", "pack", 200).SetSizes(150, 0, 0).WithTags([]string{}, []string{"vegan", "vegetarian"}).Create(rep)
+		f = model.NewFood("Bread", model.NewNutrients(264, 9.3, 46.7, 4.4, 2.3, 5, 500,
+			micronutrients(container, map[string]float64{"vitamin_c": 0, "calcium": 29, "iron": 0.6, "potassium": 97})))
+		_, _ = f.SetPurchase("Bakery", "loaf", 400).SetSizes(60, 30, 0).WithTags([]string{"gluten"}, []string{"vegetarian"}).Create(rep)
+		f = model.NewFood("Egg", model.NewNutrients(151, 12.3, 0.3, 10.3, 0, 0.3, 140,
+			micronutrients(container, map[string]float64{"vitamin_c": 0, "calcium": 51, "iron": 1.8, "potassium": 130})))
+		_, _ = f.SetPurchase("Dairy & eggs", "piece", 60).SetSizes(0, 60, 0).WithTags([]string{"egg"}, []string{"vegetarian"}).Create(rep)
+		f = model.NewFood("Coffee", model.NewNutrients(4, 0.2, 0.7, 0, 0, 0, 1,
+			micronutrients(container, map[string]float64{"vitamin_c": 0, "calcium": 2, "iron": 0, "potassium": 65})))
+		_, _ = f.SetPurchase("Beverages", "", 0).SetSizes(150, 0, 1).WithTags([]string{}, []string{"vegan", "vegetarian"}).Create(rep)
+		f = model.NewFood("Orange juice", model.NewNutrients(45, 0.7, 10.4, 0.2, 0.2, 8.4, 1,
+			micronutrients(container, map[string]float64{"vitamin_c": 50, "calcium": 11, "iron": 0.2, "potassium": 200})))
+		_, _ = f.SetPurchase("Beverages", "bottle", 1000).SetSizes(200, 0, 1.04).WithTags([]string{}, []string{"vegan", "vegetarian"}).Create(rep)
+		f = model.NewPackagedFood("Cola", "5449000000996", "", "", model.NewNutrients(42, 0, 10.6, 0, 0, 10.6, 4,
+			micronutrients(container, map[string]float64{"vitamin_c": 0, "calcium": 2, "iron": 0.1, "potassium": 2})))
+		_, _ = f.SetPurchase("Beverages", "bottle", 500).SetSizes(330, 0, 1.04).WithTags([]string{}, []string{"vegan", "vegetarian"}).Create(rep)
+
+		a := model.NewActivityType("Walking", 3.5)
+		_, _ = a.Create(rep)
+		a = model.NewActivityType("Running", 9.8)
+		_, _ = a.Create(rep)
+		a = model.NewActivityType("Cycling", 7.5)
+		_, _ = a.Create(rep)
+		a = model.NewActivityType("Swimming", 6.0)
+		_, _ = a.Create(rep)
+		a = model.NewActivityType("Strength training", 5.0)
+		_, _ = a.Create(rep)
+		a = model.NewActivityType("Yoga", 2.5)
+		_, _ = a.Create(rep)
+	}
+}
+
+// micronutrients returns the micronutrients which are configured to track.
+func micronutrients(container container.Container, amounts map[string]float64) map[string]float64 {
+	result := make(map[string]float64)
+	for _, name := range container.GetConfig().Nutrient.Micronutrients {
+		if amount, ok := amounts[name]; ok {
+			result[name] = amount
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package migration
+
+import (
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+const migrateSingleFoodMeals = "insert into meal_items (meal_id, food_id, quantity, unit) " +
+	"select m.meal_id, m.food_id, 1, ? from meals m " +
+	"where m.food_id is not null and not exists (select 1 from meal_items i where i.meal_id = m.meal_id)"
+
+const (
+	recipeVersionIndex       = "idx_recipe_versions_version"
+	createRecipeVersionIndex = "create unique index " + recipeVersionIndex + " on recipe_versions (recipe_id, version)"
+)
+
+// CreateDatabase creates the tables used in this application.
+func CreateDatabase(container container.Container) {
+	db := container.GetRepository()
+
+	if container.GetConfig().Database.Migration {
+		_ = db.DropTableIfExists(&model.UserRestriction{})
+		_ = db.DropTableIfExists(&model.FoodTag{})
+		_ = db.DropTableIfExists(&model.ShoppingListItem{})
+		_ = db.DropTableIfExists(&model.ShoppingList{})
+		_ = db.DropTableIfExists(&model.PlannedMealItem{})
+		_ = db.DropTableIfExists(&model.PlannedMeal{})
+		_ = db.DropTableIfExists(&model.MealTemplateItem{})
+		_ = db.DropTableIfExists(&model.MealTemplate{})
+		_ = db.DropTableIfExists(&model.HydrationEntry{})
+		_ = db.DropTableIfExists(&model.Activity{})
+		_ = db.DropTableIfExists(&model.ActivityType{})
+		_ = db.DropTableIfExists(&model.WeightEntry{})
+		_ = db.DropTableIfExists(&model.Goal{})
+		_ = db.DropTableIfExists(&model.MealItemNutrient{})
+		_ = db.DropTableIfExists(&model.MealItem{})
+		_ = db.DropTableIfExists(&model.RecipeIngredient{})
+		_ = db.DropTableIfExists(&model.RecipeVersion{})
+		_ = db.DropTableIfExists(&model.Recipe{})
+		_ = db.DropTableIfExists(&model.Meal{})
+		_ = db.DropTableIfExists(&model.FoodNutrient{})
+		_ = db.DropTableIfExists(&model.Food{})
+		_ = db.DropTableIfExists(&model.User{})
+	}
+
+	_ = db.AutoMigrate(&model.User{})
+	_ = db.AutoMigrate(&model.Food{})
+	_ = db.AutoMigrate(&model.FoodNutrient{})
+	_ = db.AutoMigrate(&model.Meal{})
+	_ = db.AutoMigrate(&model.Recipe{})
+	_ = db.AutoMigrate(&model.RecipeVersion{})
+	_ = db.AutoMigrate(&model.RecipeIngredient{})
+	_ = db.AutoMigrate(&model.MealItem{})
+	_ = db.AutoMigrate(&model.MealItemNutrient{})
+	_ = db.AutoMigrate(&model.Goal{})
+	_ = db.AutoMigrate(&model.WeightEntry{})
+	_ = db.AutoMigrate(&model.ActivityType{})
+	_ = db.AutoMigrate(&model.Activity{})
+	_ = db.AutoMigrate(&model.HydrationEntry{})
+	_ = db.AutoMigrate(&model.MealTemplate{})
+	_ = db.AutoMigrate(&model.MealTemplateItem{})
+	_ = db.AutoMigrate(&model.PlannedMeal{})
+	_ = db.AutoMigrate(&model.PlannedMealItem{})
+	_ = db.AutoMigrate(&model.ShoppingList{})
+	_ = db.AutoMigrate(&model.ShoppingListItem{})
+	_ = db.AutoMigrate(&model.FoodTag{})
+	_ = db.AutoMigrate(&model.UserRestriction{})
+
+	indexRecipeVersions(container)
+	migrateMealItems(container)
+	snapshotMealItems(container)
+	snapshotHydrationEntries(container)
+	classifyMeals(container)
+	normalizeTimes(container)
+	setupFoodSearch(container)
+}
+
+// indexRecipeVersions creates the unique index on the versions of a recipe,
+// so that the concurrent updates of a recipe cannot both write the same version.
+// The versions which such updates have already duplicated are renumbered first, otherwise the index cannot be created.
+func indexRecipeVersions(container container.Container) {
+	db := container.GetRepository()
+	if db.HasIndex(&model.RecipeVersion{}, recipeVersionIndex) {
+		return
+	}
+	recipe := model.Recipe{}
+	if err := db.Transaction(func(txrep repository.Repository) error {
+		if err := recipe.RenumberDuplicateVersions(txrep); err != nil {
+			return err
+		}
+		return txrep.Exec(createRecipeVersionIndex).Error
+	}); err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// migrateMealItems converts the meals which have a single food_id into the meals which have one item.
+func migrateMealItems(container container.Container) {
+	db := container.GetRepository()
+	if !db.HasColumn(&model.Meal{}, "food_id") {
+		return
+	}
+	if err := db.Exec(migrateSingleFoodMeals, model.UnitServing).Error; err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// snapshotMealItems snapshots the nutrients of the items which were logged before the nutrients were snapshotted.
+func snapshotMealItems(container container.Container) {
+	db := container.GetRepository()
+	item := model.MealItem{}
+	if err := db.Transaction(func(txrep repository.Repository) error {
+		return item.Snapshot(txrep)
+	}); err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// snapshotHydrationEntries snapshots the nutrients of the beverages which were logged before the nutrients were snapshotted.
+func snapshotHydrationEntries(container container.Container) {
+	db := container.GetRepository()
+	entry := model.HydrationEntry{}
+	if err := db.Transaction(func(txrep repository.Repository) error {
+		return entry.Snapshot(txrep)
+	}); err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// classifyMeals infers the meal types of the meals which were logged before the meal types were introduced.
+func classifyMeals(container container.Container) {
+	db := container.GetRepository()
+	meal := model.Meal{}
+	if err := db.Transaction(func(txrep repository.Repository) error {
+		return meal.ClassifyMealTypes(txrep)
+	}); err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// normalizeTimes converts the times of the meals, the beverages and the activities which were stored
+// with the offsets of the time zones of their users to UTC. Only SQLite keeps the offsets, and it compares the times
+// as strings, so the offsets break the order of them and move them into the wrong days.
+func normalizeTimes(container container.Container) {
+	if container.GetConfig().Database.Dialect == repository.POSTGRES || container.GetConfig().Database.Dialect == repository.MYSQL {
+		return
+	}
+	db := container.GetRepository()
+	meal := model.Meal{}
+	entry := model.HydrationEntry{}
+	activity := model.Activity{}
+	if err := db.Transaction(func(txrep repository.Repository) error {
+		if err := meal.NormalizeMealTimes(txrep); err != nil {
+			return err
+		}
+		if err := entry.NormalizeDrankTimes(txrep); err != nil {
+			return err
+		}
+		return activity.NormalizePerformedTimes(txrep)
+	}); err != nil {
+		container.GetLogger().GetZapLogger().Errorf(err.Error())
+	}
+}
+
+// setupFoodSearch creates the index for the food search. If the database does not support it,
+// the foods are searched by the in-memory index instead.
+func setupFoodSearch(container container.Container) {
+	db := container.GetRepository()
+	if err := db.SetupTextSearch("foods", "food_id", "food_name"); err != nil {
+		container.GetLogger().GetZapLogger().Infof("Use the in-memory index for the food search: %s", err.Error())
+	}
+}
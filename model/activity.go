@@ -0,0 +1,183 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// intensityFactors are the multipliers of MET by the intensity of an activity.
+var intensityFactors = map[string]float64{
+	"low":      0.8,
+	"moderate": 1.0,
+	"high":     1.2,
+}
+
+// ActivityType defines struct of the master data of activities and their MET.
+type ActivityType struct {
+	activity_type_id uint    `gorm:"primary_key" json:"id"`
+	activity_name    string  `json:"activity_name"`
+	met              float64 `json:"met"`
+}
+
+// Activity defines struct of an exercise or an activity of a user.
+type Activity struct {
+	activity_id      uint      `gorm:"primary_key" json:"id"`
+	user_id          uint      `json:"user_id"`
+	activity_type_id uint      `json:"activity_type_id"`
+	duration         float64   `json:"duration"`
+	intensity        string    `json:"intensity"`
+	calories_burned  float64   `json:"calories_burned"`
+	performed_at     time.Time `json:"performed_at"`
+}
+
+// TableName returns the table name of ActivityType struct and it is used by gorm.
+func (ActivityType) TableName() string {
+	return "activity_types"
+}
+
+// TableName returns the table name of Activity struct and it is used by gorm.
+func (Activity) TableName() string {
+	return "activities"
+}
+
+// NewActivityType is constructor
+func NewActivityType(activity_name string, met float64) *ActivityType {
+	return &ActivityType{activity_name: activity_name, met: met}
+}
+
+// NewActivity is constructor. If calories_burned is 0, it is estimated from MET when the Activity is persisted.
+func NewActivity(user *User, activity_type_id uint, duration float64, intensity string, calories_burned float64, performed_at time.Time) *Activity {
+	return &Activity{user_id: user.user_id, activity_type_id: activity_type_id, duration: duration,
+		intensity: intensity, calories_burned: calories_burned, performed_at: performed_at}
+}
+
+// FindByID returns an ActivityType full matched given ID.
+func (t *ActivityType) FindByID(rep repository.Repository, id uint) optional.Option[*ActivityType] {
+	var activityType ActivityType
+	if err := rep.Where("activity_type_id = ?", id).First(&activityType).Error; err != nil {
+		return optional.None[*ActivityType]()
+	}
+	return optional.Some(&activityType)
+}
+
+// FindAll returns all ActivityTypes of the ActivityType table.
+func (t *ActivityType) FindAll(rep repository.Repository) (*[]ActivityType, error) {
+	var types []ActivityType
+	if err := rep.Find(&types).Error; err != nil {
+		return nil, err
+	}
+	return &types, nil
+}
+
+// Create persists this ActivityType data.
+func (t *ActivityType) Create(rep repository.Repository) (*ActivityType, error) {
+	if err := rep.Select("activity_name", "met").Create(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FindByID returns an Activity of a given user full matched given ID.
+func (a *Activity) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*Activity] {
+	var activity Activity
+	if err := rep.Where("activity_id = ? and user_id = ?", id, user.user_id).First(&activity).Error; err != nil {
+		return optional.None[*Activity]()
+	}
+	return optional.Some(&activity)
+}
+
+// FindByUser returns the Activities of a given user, the newest first.
+func (a *Activity) FindByUser(rep repository.Repository, user *User) (*[]Activity, error) {
+	var activities []Activity
+	if err := rep.Where("user_id = ?", user.user_id).Order("performed_at desc").Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return &activities, nil
+}
+
+// SumCaloriesByUserAndDate returns the total calories which a given user has burned in a given day.
+func (a *Activity) SumCaloriesByUserAndDate(rep repository.Repository, user *User, date time.Time) (float64, error) {
+	var total float64
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if err := rep.Model(&Activity{}).Select("coalesce(sum(calories_burned), 0)").
+		Where("user_id = ? and performed_at >= ? and performed_at < ?", user.user_id, from.UTC(), from.AddDate(0, 0, 1).UTC()).
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Create persists this Activity data. performed_at is stored in UTC in the same way as meal_at of the Meals.
+func (a *Activity) Create(rep repository.Repository) (*Activity, error) {
+	a.performed_at = a.performed_at.UTC()
+	if err := a.estimate(rep); err != nil {
+		return nil, err
+	}
+	if err := rep.Select("user_id", "activity_type_id", "duration", "intensity", "calories_burned", "performed_at").Create(a).Error; err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Update updates this Activity data by given Activity.
+func (a *Activity) Update(rep repository.Repository, activity *Activity) (*Activity, error) {
+	a.activity_type_id = activity.activity_type_id
+	a.duration = activity.duration
+	a.intensity = activity.intensity
+	a.calories_burned = activity.calories_burned
+	a.performed_at = activity.performed_at.UTC()
+	if err := a.estimate(rep); err != nil {
+		return nil, err
+	}
+	if err := rep.Model(a).Select("activity_type_id", "duration", "intensity", "calories_burned", "performed_at").Updates(a).Error; err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Delete deletes this Activity data.
+func (a *Activity) Delete(rep repository.Repository) (*Activity, error) {
+	if err := rep.Delete(a).Error; err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NormalizePerformedTimes converts performed_at of the Activities which were stored with the offset of a time zone to UTC.
+func (a *Activity) NormalizePerformedTimes(rep repository.Repository) error {
+	return normalizeTimes(rep, "activities", "activity_id", "performed_at")
+}
+
+// estimate computes the calories burned by MET x weight (kg) x duration (hours)
+// unless the calories are given. It uses the latest weight of the user until the activity.
+func (a *Activity) estimate(rep repository.Repository) error {
+	activityType := ActivityType{}
+	t, err := activityType.FindByID(rep, a.activity_type_id).Take()
+	if err != nil {
+		return errors.New("activity type is not found")
+	}
+	if a.calories_burned > 0 {
+		return nil
+	}
+
+	entry := WeightEntry{}
+	w, err := entry.FindLatestByUserAndDate(rep, &User{user_id: a.user_id}, a.performed_at).Take()
+	if err != nil {
+		return errors.New("please register the weight to estimate the calories burned")
+	}
+
+	factor, ok := intensityFactors[a.intensity]
+	if !ok {
+		factor = intensityFactors["moderate"]
+	}
+	a.calories_burned = t.met * factor * w.weight * a.duration / 60
+	return nil
+}
+
+// ToString is return string of object
+func (a *Activity) ToString() string {
+	return toString(a)
+}
@@ -3,15 +3,15 @@ package model
 import "encoding/json"
 
 type DomainObject interface {
-	User | Meal | Food
+	User | Meal | Food | MealItem | Goal | WeightEntry | Activity | HydrationEntry | Recipe | MealTemplate | PlannedMeal | ShoppingList
 }
 
 func toString[T DomainObject](o *T) string {
 	var bytes []byte
 	var err error
-	if bytes, err = json.Marshal(0); err != nil {
+	if bytes, err = json.Marshal(o); err != nil {
 		return ""
 	}
 
-	return toString(bytes)
+	return string(bytes)
 }
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageDuration  string = "Please enter the duration in minutes greater than 0."
+	ValidationErrMessageIntensity string = "Please enter the intensity with low, moderate or high."
+	ValidationErrMessageBurned    string = "Please enter the calories burned 0 or greater."
+)
+
+// ActivityDto defines a data transfer object for Activity.
+// If calories_burned is omitted, it is estimated from the MET of the activity type.
+type ActivityDto struct {
+	activity_type_id uint      `validate:"required" json:"activity_type_id"`
+	duration         float64   `validate:"required,gt=0" json:"duration"`
+	intensity        string    `validate:"required,oneof=low moderate high" json:"intensity"`
+	calories_burned  float64   `validate:"gte=0" json:"calories_burned"`
+	performed_at     time.Time `validate:"required" json:"performed_at"`
+}
+
+// NewActivityDto is constructor.
+func NewActivityDto() *ActivityDto {
+	return &ActivityDto{}
+}
+
+// Create creates an Activity model of a given user from this DTO.
+func (a *ActivityDto) Create(user *model.User) *model.Activity {
+	return model.NewActivity(user, a.activity_type_id, a.duration, a.intensity, a.calories_burned, a.performed_at)
+}
+
+// Validate performs validation check for the each item.
+func (a *ActivityDto) Validate() map[string]string {
+	return validateDto(a)
+}
+
+// ToString is return string of object
+func (a *ActivityDto) ToString() (string, error) {
+	bytes, err := json.Marshal(a)
+	return string(bytes), err
+}
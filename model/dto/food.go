@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageFoodName     string = "Please enter the name with 1 to 100 characters."
+	ValidationErrMessageFoodNutrient string = "Please enter the amount per 100 g 0 or greater."
+	ValidationErrMessageFoodGlobal   string = "Only the administrator can add a food to the global catalog."
+	ValidationErrMessageFoodMicro    string = "Please enter the micronutrients which are tracked."
+)
+
+// FoodDto defines a data transfer object for the Food which a user creates or edits.
+// The amounts are per 100 g. Only the administrator can create a Food of the global catalog.
+type FoodDto struct {
+	food_name      string             `validate:"required,max=100" json:"food_name"`
+	calo_amount    float64            `validate:"gte=0" json:"calo_amount"`
+	protein        float64            `validate:"gte=0" json:"protein"`
+	carbohydrate   float64            `validate:"gte=0" json:"carbohydrate"`
+	fat            float64            `validate:"gte=0" json:"fat"`
+	fiber          float64            `validate:"gte=0" json:"fiber"`
+	sugar          float64            `validate:"gte=0" json:"sugar"`
+	sodium         float64            `validate:"gte=0" json:"sodium"`
+	micronutrients map[string]float64 `validate:"dive,gte=0" json:"micronutrients"`
+	serving_size   float64            `validate:"gte=0" json:"serving_size"`
+	piece_size     float64            `validate:"gte=0" json:"piece_size"`
+	density        float64            `validate:"gte=0" json:"density"`
+	allergens      []string           `validate:"dive,oneof=gluten nuts peanuts dairy egg soy fish shellfish sesame" json:"allergens"`
+	diets          []string           `validate:"dive,oneof=vegan vegetarian halal kosher keto" json:"diets"`
+	global         bool               `json:"global"`
+}
+
+// NewFoodDto is constructor.
+func NewFoodDto() *FoodDto {
+	return &FoodDto{}
+}
+
+// Create creates a Food model from this DTO. It is the private Food of given user unless it is global.
+// The tags of the kind which is omitted are nil, so that editing the Food keeps its current tags.
+func (f *FoodDto) Create(user *model.User) *model.Food {
+	nutrients := model.NewNutrients(f.calo_amount, f.protein, f.carbohydrate, f.fat, f.fiber, f.sugar, f.sodium, f.micronutrients)
+	var food *model.Food
+	if f.global {
+		food = model.NewFood(f.food_name, nutrients)
+	} else {
+		food = model.NewUserFood(user, f.food_name, nutrients)
+	}
+	return food.SetSizes(f.serving_size, f.piece_size, f.density).WithTags(optionalTags(f.allergens), optionalTags(f.diets))
+}
+
+// optionalTags returns the unique tags, or nil if the tags are omitted.
+func optionalTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	return uniqueTags(tags)
+}
+
+// Validate performs validation check for the each item.
+func (f *FoodDto) Validate() map[string]string {
+	return validateDto(f)
+}
+
+// ValidateFor performs validation check for the each item, the micronutrients which must be tracked
+// and the global catalog which only the administrator can add a Food to.
+func (f *FoodDto) ValidateFor(user *model.User, tracked []string) map[string]string {
+	if errors := f.Validate(); errors != nil {
+		return errors
+	}
+	for name := range f.micronutrients {
+		found := false
+		for _, t := range tracked {
+			found = found || t == name
+		}
+		if !found {
+			return map[string]string{"micronutrients": ValidationErrMessageFoodMicro}
+		}
+	}
+	if f.global && (user == nil || !user.IsAdmin()) {
+		return map[string]string{"global": ValidationErrMessageFoodGlobal}
+	}
+	return nil
+}
+
+// ToString is return string of object
+func (f *FoodDto) ToString() (string, error) {
+	bytes, err := json.Marshal(f)
+	return string(bytes), err
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+)
+
+const (
+	ValidationErrMessageImportFile   string = "Please enter the file name in the import directory."
+	ValidationErrMessageImportFormat string = "Please enter the format with csv, usda or off."
+)
+
+// FoodImportDto defines a data transfer object for importing Foods from a file.
+type FoodImportDto struct {
+	file   string `validate:"required" json:"file"`
+	format string `validate:"omitempty,oneof=csv usda off" json:"format"`
+}
+
+// NewFoodImportDto is constructor.
+func NewFoodImportDto() *FoodImportDto {
+	return &FoodImportDto{}
+}
+
+// File returns the file name in the import directory.
+func (f *FoodImportDto) File() string {
+	return f.file
+}
+
+// Format returns the format of the file. It is empty if it should be detected by the extension.
+func (f *FoodImportDto) Format() string {
+	return f.format
+}
+
+// Validate performs validation check for the each item.
+func (f *FoodImportDto) Validate() map[string]string {
+	return validateDto(f)
+}
+
+// ToString is return string of object
+func (f *FoodImportDto) ToString() (string, error) {
+	bytes, err := json.Marshal(f)
+	return string(bytes), err
+}
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageFoodMerge   string = "Please enter the ID of the duplicate food to merge."
+	ValidationErrMessageFoodGlobals string = "Only the foods of the global catalog can be merged."
+)
+
+// FoodMergeDto defines a data transfer object for the duplicate Food which is merged into another Food.
+type FoodMergeDto struct {
+	duplicate_id uint `validate:"required" json:"duplicate_id"`
+}
+
+// NewFoodMergeDto is constructor.
+func NewFoodMergeDto() *FoodMergeDto {
+	return &FoodMergeDto{}
+}
+
+// DuplicateID returns the ID of the duplicate Food.
+func (f *FoodMergeDto) DuplicateID() uint {
+	return f.duplicate_id
+}
+
+// Validate performs validation check for the each item.
+func (f *FoodMergeDto) Validate() map[string]string {
+	return validateDto(f)
+}
+
+// ValidateFor performs validation check for the each item and checks that the duplicate differs from the Food of given ID.
+func (f *FoodMergeDto) ValidateFor(food_id uint) map[string]string {
+	if errors := f.Validate(); errors != nil {
+		return errors
+	}
+	if f.duplicate_id == food_id {
+		return map[string]string{"duplicate_id": ValidationErrMessageFoodMerge}
+	}
+	return nil
+}
+
+// ValidateFoods checks that given duplicate exists, and that it and given Food are in the global catalog.
+func (f *FoodMergeDto) ValidateFoods(food *model.Food, duplicate *model.Food) map[string]string {
+	if duplicate == nil {
+		return map[string]string{"duplicate_id": ValidationErrMessageFoodMerge}
+	}
+	if food.IsDeleted() || !food.IsGlobal() || !duplicate.IsGlobal() {
+		return map[string]string{"duplicate_id": ValidationErrMessageFoodGlobals}
+	}
+	return nil
+}
+
+// ToString is return string of object
+func (f *FoodMergeDto) ToString() (string, error) {
+	bytes, err := json.Marshal(f)
+	return string(bytes), err
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"encoding/json"
+)
+
+const (
+	ValidationErrMessageFoodSize string = "Please enter the size 0 or greater. 0 means that the food has no such size."
+)
+
+// FoodSizeDto defines a data transfer object for the sizes of a Food which convert the portions to grams.
+type FoodSizeDto struct {
+	serving_size float64 `validate:"gte=0" json:"serving_size"`
+	piece_size   float64 `validate:"gte=0" json:"piece_size"`
+	density      float64 `validate:"gte=0" json:"density"`
+}
+
+// NewFoodSizeDto is constructor.
+func NewFoodSizeDto() *FoodSizeDto {
+	return &FoodSizeDto{}
+}
+
+// ServingSize returns the grams of a serving.
+func (f *FoodSizeDto) ServingSize() float64 {
+	return f.serving_size
+}
+
+// PieceSize returns the grams of a piece.
+func (f *FoodSizeDto) PieceSize() float64 {
+	return f.piece_size
+}
+
+// Density returns the grams per milliliter.
+func (f *FoodSizeDto) Density() float64 {
+	return f.density
+}
+
+// Validate performs validation check for the each item.
+func (f *FoodSizeDto) Validate() map[string]string {
+	return validateDto(f)
+}
+
+// ToString is return string of object
+func (f *FoodSizeDto) ToString() (string, error) {
+	bytes, err := json.Marshal(f)
+	return string(bytes), err
+}
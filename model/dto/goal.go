@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	ValidationErrMessageGoalCalories string = "Please enter the calories greater than 0."
+	ValidationErrMessageGoalRatio    string = "Please enter the ratio between 0 and 100."
+	ValidationErrMessageGoalRatioSum string = "Please enter the ratios which add up to 100."
+	ValidationErrMessageDate         string = "Please enter the date with YYYY-MM-DD."
+	ValidationErrMessageDateRange    string = "Please enter the date on or after the start date."
+)
+
+// ratioTolerance is the tolerance of the sum of the ratios for rounding such as 33.3, 33.3 and 33.4.
+const ratioTolerance = 0.01
+
+// GoalDto defines a data transfer object for Goal.
+type GoalDto struct {
+	calories           float64 `validate:"required,gt=0" json:"calories"`
+	protein_ratio      float64 `validate:"gte=0,lte=100" json:"protein_ratio"`
+	carbohydrate_ratio float64 `validate:"gte=0,lte=100" json:"carbohydrate_ratio"`
+	fat_ratio          float64 `validate:"gte=0,lte=100" json:"fat_ratio"`
+	effective_from     string  `validate:"omitempty,datetime=2006-01-02" json:"effective_from"`
+}
+
+// NewGoalDto is constructor.
+func NewGoalDto() *GoalDto {
+	return &GoalDto{}
+}
+
+// Create creates a Goal model of a given user from this DTO.
+// The Goal is effective from today in given time zone of the user if effective_from is omitted.
+func (g *GoalDto) Create(user *model.User, loc *time.Location) *model.Goal {
+	from, _ := util.ParseDateIn(g.effective_from, loc)
+	return model.NewGoal(user, g.calories, g.protein_ratio, g.carbohydrate_ratio, g.fat_ratio, from)
+}
+
+// Validate performs validation check for the each item.
+func (g *GoalDto) Validate() map[string]string {
+	result := validateDto(g)
+	if math.Abs(g.protein_ratio+g.carbohydrate_ratio+g.fat_ratio-100) > ratioTolerance {
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result["ratio"] = ValidationErrMessageGoalRatioSum
+	}
+	return result
+}
+
+// ToString is return string of object
+func (g *GoalDto) ToString() (string, error) {
+	bytes, err := json.Marshal(g)
+	return string(bytes), err
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageVolume       string = "Please enter the volume in ml greater than 0."
+	ValidationErrMessageBeverageType string = "Please enter the beverage type with water, tea, coffee, juice, soda, milk or other."
+)
+
+// HydrationDto defines a data transfer object for HydrationEntry.
+// food_id is optional and refers to the Food of the beverage such as juice or soda.
+type HydrationDto struct {
+	volume        float64   `validate:"required,gt=0" json:"volume"`
+	beverage_type string    `validate:"required,oneof=water tea coffee juice soda milk other" json:"beverage_type"`
+	food_id       *uint     `json:"food_id"`
+	drank_at      time.Time `validate:"required" json:"drank_at"`
+}
+
+// NewHydrationDto is constructor.
+func NewHydrationDto() *HydrationDto {
+	return &HydrationDto{}
+}
+
+// Create creates a HydrationEntry model of a given user from this DTO.
+func (h *HydrationDto) Create(user *model.User) *model.HydrationEntry {
+	return model.NewHydrationEntry(user, h.volume, h.beverage_type, h.food_id, h.drank_at)
+}
+
+// Validate performs validation check for the each item.
+func (h *HydrationDto) Validate() map[string]string {
+	return validateDto(h)
+}
+
+// ToString is return string of object
+func (h *HydrationDto) ToString() (string, error) {
+	bytes, err := json.Marshal(h)
+	return string(bytes), err
+}
@@ -2,27 +2,65 @@ package dto
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
 	"gopkg.in/go-playground/validator.v9"
 )
 
 const (
-	required string = "required"
+	required        string = "required"
+	requiredWithout string = "required_without"
+	max             string = "max"
+	min             string = "min"
+	gt              string = "gt"
+	oneof           string = "oneof"
+	gte             string = "gte"
+	lte             string = "lte"
+	datetime        string = "datetime"
+	numeric         string = "numeric"
+	unit            string = "unit"
+	timezone        string = "timezone"
 )
 
 const (
-	ValidationErrMessageMealName string = "Please enter the name with 3 to 50 characters."
-	ValidationErrMessageDefault string = "This field is required."
+	ValidationErrMessageMealName     string = "Please enter the name with 3 to 50 characters."
+	ValidationErrMessageDefault      string = "This field is required."
+	ValidationErrMessageMealItems    string = "Please enter at least one food item or recipe."
+	ValidationErrMessageMealQuantity string = "Please enter the quantity greater than 0."
+	ValidationErrMessageMealUnit     string = "Please enter the unit with g, kg, mg, oz, lb, ml, l, tsp, tbsp, cup, fl_oz, piece or serving."
+	ValidationErrMessageUnitFood     string = "The food cannot be measured in the unit. Please enter the unit of mass or the unit which the food has the size of."
+	ValidationErrMessageMealFood     string = "Please enter the food ID or the barcode."
+	ValidationErrMessageMealBarcode  string = "Please enter the barcode with 12 or 13 digits."
+	ValidationErrMessageMealType     string = "Please enter the meal type with breakfast, lunch, dinner or snack."
 )
 
 // MealDto defines a data transfer object for Meal.
+// It has no owner because the Meal always belongs to the logged-in user.
 type MealDto struct {
-	meal_name string    `validate:"required" json:"meal_name"`
-	user_id   string    `validate:"required" `json:"food_id"`
-	food_id   uint      `validate:"required" `json:"food_id"`
-	meal_at   time.Time `validate:"required" `json:"meal_at"`
+	meal_name  string          `validate:"required" json:"meal_name"`
+	meal_at    time.Time       `validate:"required" json:"meal_at"`
+	meal_type  string          `validate:"omitempty,oneof=breakfast lunch dinner snack" json:"meal_type"`
+	items      []MealItemDto   `validate:"required_without=recipes,dive" json:"items"`
+	recipes    []MealRecipeDto `validate:"required_without=items,dive" json:"recipes"`
+	keep_items bool            `json:"-"`
+}
+
+// MealItemDto defines a data transfer object for the food item of a Meal.
+type MealItemDto struct {
+	food_id  uint    `validate:"required_without=barcode" json:"food_id"`
+	barcode  string  `validate:"omitempty,numeric,min=12,max=13" json:"barcode"`
+	quantity float64 `validate:"required,gt=0" json:"quantity"`
+	unit     string  `validate:"required,unit" json:"unit"`
+}
+
+// MealRecipeDto defines a data transfer object for the Recipe of a Meal.
+type MealRecipeDto struct {
+	recipe_id uint    `validate:"required" json:"recipe_id"`
+	servings  float64 `validate:"required,gt=0" json:"servings"`
 }
 
 // NewMealDto is constructor.
@@ -30,9 +68,45 @@ func NewMealDto() *MealDto {
 	return &MealDto{}
 }
 
-// Create creates a Meal model from this DTO.
-func (m *MealDto) Create() *model.Meal {
-	return model.NewMeal(m.user_id, m.food_id, m.meal_name, m.meal_at)
+// MergeMealDto applies given JSON Merge Patch to given Meal and returns the result as a MealDto.
+// The items of the Meal are kept as they are unless the patch has the items or the recipes.
+// The owner of the Meal cannot be patched.
+func MergeMealDto(meal *model.Meal, patch []byte) (*MealDto, error) {
+	doc, err := json.Marshal(meal)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := util.MergePatch(doc, patch)
+	if err != nil {
+		return nil, err
+	}
+	dto := NewMealDto()
+	if err := json.Unmarshal(merged, dto); err != nil {
+		return nil, err
+	}
+	dto.keep_items = !util.PatchHasMember(patch, "items", "recipes")
+	return dto, nil
+}
+
+// Create creates a Meal model of a given user from this DTO.
+// The Meal has neither items nor recipes if the items are kept by MergeMealDto.
+func (m *MealDto) Create(user *model.User) *model.Meal {
+	if m.keep_items {
+		return model.NewMeal(m.meal_name, user, m.meal_at, nil, nil).SetMealType(m.meal_type)
+	}
+	items := make([]model.MealItem, len(m.items))
+	for i := range m.items {
+		if m.items[i].food_id == 0 {
+			items[i] = *model.NewScannedMealItem(m.items[i].barcode, m.items[i].quantity, m.items[i].unit)
+			continue
+		}
+		items[i] = *model.NewMealItem(m.items[i].food_id, m.items[i].quantity, m.items[i].unit)
+	}
+	recipes := make([]model.MealRecipe, len(m.recipes))
+	for i := range m.recipes {
+		recipes[i] = *model.NewMealRecipe(m.recipes[i].recipe_id, m.recipes[i].servings)
+	}
+	return model.NewMeal(m.meal_name, user, m.meal_at, items, recipes).SetMealType(m.meal_type)
 }
 
 // Validate performs validation check for the each item.
@@ -41,7 +115,14 @@ func (b *MealDto) Validate() map[string]string {
 }
 
 func validateDto(b interface{}) map[string]string {
-	err := validator.New().Struct(b)
+	validate := validator.New()
+	_ = validate.RegisterValidation(unit, func(fl validator.FieldLevel) bool {
+		return util.IsUnit(fl.Field().String())
+	})
+	_ = validate.RegisterValidation(timezone, func(fl validator.FieldLevel) bool {
+		return util.IsTimezone(fl.Field().String())
+	})
+	err := validate.Struct(b)
 	if err == nil {
 		return nil
 	}
@@ -57,16 +138,212 @@ func validateDto(b interface{}) map[string]string {
 func createErrorMessages(errors validator.ValidationErrors) map[string]string {
 	result := make(map[string]string)
 	for i := range errors {
-		switch errors[i].StructField() {
+		switch fieldName(errors[i]) {
 		case "meal_name":
 			switch errors[i].Tag() {
 			case required:
 				result["meal_name"] = ValidationErrMessageMealName
 			}
-		case "user_id":
+		case "meal_at", "planned_at":
 			switch errors[i].Tag() {
 			case required:
-				result["user_id"] = ValidationErrMessageDefault
+				result[errors[i].StructField()] = ValidationErrMessageDefault
+			}
+		case "items", "recipes":
+			switch errors[i].Tag() {
+			case requiredWithout:
+				result[errors[i].StructField()] = ValidationErrMessageMealItems
+			case required, min:
+				result[errors[i].StructField()] = ValidationErrMessagePlanItems
+			}
+		case "recipe_name":
+			switch errors[i].Tag() {
+			case required, max:
+				result["recipe_name"] = ValidationErrMessageRecipeName
+			}
+		case "servings":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["servings"] = ValidationErrMessageServings
+			}
+		case "ingredients":
+			switch errors[i].Tag() {
+			case required, min:
+				result["ingredients"] = ValidationErrMessageRecipeIngredients
+			}
+		case "food_id", "recipe_id":
+			switch errors[i].Tag() {
+			case required:
+				result[errors[i].StructField()] = ValidationErrMessageDefault
+			case requiredWithout:
+				result[errors[i].StructField()] = ValidationErrMessageMealFood
+			case numeric:
+				result[errors[i].StructField()] = ValidationErrMessageNumber
+			}
+		case "operations":
+			switch errors[i].Tag() {
+			case required, min, max:
+				result["operations"] = ValidationErrMessageBatchOperations
+			}
+		case "op":
+			switch errors[i].Tag() {
+			case required, oneof:
+				result["op"] = ValidationErrMessageBatchOp
+			}
+		case "min_calories", "max_calories", "page", "size":
+			switch errors[i].Tag() {
+			case numeric:
+				result[errors[i].StructField()] = ValidationErrMessageNumber
+			}
+		case "barcode":
+			switch errors[i].Tag() {
+			case numeric, min, max:
+				result["barcode"] = ValidationErrMessageMealBarcode
+			}
+		case "quantity":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["quantity"] = ValidationErrMessageMealQuantity
+			}
+		case "unit":
+			switch errors[i].Tag() {
+			case required, unit:
+				result["unit"] = ValidationErrMessageMealUnit
+			}
+		case "calories":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["calories"] = ValidationErrMessageGoalCalories
+			}
+		case "protein_ratio", "carbohydrate_ratio", "fat_ratio":
+			switch errors[i].Tag() {
+			case gte, lte:
+				result[errors[i].StructField()] = ValidationErrMessageGoalRatio
+			}
+		case "template_name":
+			switch errors[i].Tag() {
+			case required, max:
+				result["template_name"] = ValidationErrMessageTemplateName
+			}
+		case "meal_id":
+			switch errors[i].Tag() {
+			case requiredWithout:
+				result["meal_id"] = ValidationErrMessageTemplateSource
+			}
+		case "list_name":
+			switch errors[i].Tag() {
+			case required, max:
+				result["list_name"] = ValidationErrMessageShoppingListName
+			}
+		case "effective_from", "birth_date", "date", "from", "to":
+			switch errors[i].Tag() {
+			case required, datetime:
+				result[errors[i].StructField()] = ValidationErrMessageDate
+			}
+		case "allergens":
+			switch errors[i].Tag() {
+			case oneof:
+				result["allergens"] = ValidationErrMessageAllergens
+			}
+		case "diets":
+			switch errors[i].Tag() {
+			case oneof:
+				result["diets"] = ValidationErrMessageDiets
+			}
+		case "mode":
+			switch errors[i].Tag() {
+			case oneof:
+				result["mode"] = ValidationErrMessageRestrictionMode
+			}
+		case "height":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["height"] = ValidationErrMessageHeight
+			}
+		case "sex":
+			switch errors[i].Tag() {
+			case required, oneof:
+				result["sex"] = ValidationErrMessageSex
+			}
+		case "activity_level":
+			switch errors[i].Tag() {
+			case required, oneof:
+				result["activity_level"] = ValidationErrMessageActivityLevel
+			}
+		case "timezone":
+			switch errors[i].Tag() {
+			case timezone:
+				result["timezone"] = ValidationErrMessageTimezone
+			}
+		case "meal_type":
+			switch errors[i].Tag() {
+			case oneof:
+				result["meal_type"] = ValidationErrMessageMealType
+			}
+		case "weight":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["weight"] = ValidationErrMessageWeight
+			}
+		case "volume":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["volume"] = ValidationErrMessageVolume
+			}
+		case "beverage_type":
+			switch errors[i].Tag() {
+			case required, oneof:
+				result["beverage_type"] = ValidationErrMessageBeverageType
+			}
+		case "file":
+			switch errors[i].Tag() {
+			case required:
+				result["file"] = ValidationErrMessageImportFile
+			}
+		case "format":
+			switch errors[i].Tag() {
+			case oneof:
+				result["format"] = ValidationErrMessageImportFormat
+			}
+		case "measured_at", "performed_at", "activity_type_id", "drank_at":
+			switch errors[i].Tag() {
+			case required:
+				result[errors[i].StructField()] = ValidationErrMessageDefault
+			}
+		case "duration":
+			switch errors[i].Tag() {
+			case required, gt:
+				result["duration"] = ValidationErrMessageDuration
+			}
+		case "intensity":
+			switch errors[i].Tag() {
+			case required, oneof:
+				result["intensity"] = ValidationErrMessageIntensity
+			}
+		case "food_name":
+			switch errors[i].Tag() {
+			case required, max:
+				result["food_name"] = ValidationErrMessageFoodName
+			}
+		case "calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium", "micronutrients":
+			switch errors[i].Tag() {
+			case gte:
+				result[fieldName(errors[i])] = ValidationErrMessageFoodNutrient
+			}
+		case "serving_size", "piece_size", "density":
+			switch errors[i].Tag() {
+			case gte:
+				result[errors[i].StructField()] = ValidationErrMessageFoodSize
+			}
+		case "duplicate_id":
+			switch errors[i].Tag() {
+			case required:
+				result["duplicate_id"] = ValidationErrMessageFoodMerge
+			}
+		case "calories_burned":
+			switch errors[i].Tag() {
+			case gte:
+				result["calories_burned"] = ValidationErrMessageBurned
 			}
 		}
 	}
@@ -78,3 +355,25 @@ func (u *MealDto) ToString() (string, error) {
 	bytes, err := json.Marshal(u)
 	return string(bytes), err
 }
+
+// UnitErrorMessages returns the validation error of the unit if given error is the portion of a food
+// which cannot be converted to grams. It returns nil for the other errors.
+func UnitErrorMessages(err error) map[string]string {
+	var uerr *model.UnitError
+	if !errors.As(err, &uerr) {
+		return nil
+	}
+	if errors.Is(err, util.ErrUnknownUnit) {
+		return map[string]string{"unit": ValidationErrMessageMealUnit}
+	}
+	return map[string]string{"unit": ValidationErrMessageUnitFood}
+}
+
+// fieldName returns the name of the field which has an error without the index of the element of a slice.
+func fieldName(err validator.FieldError) string {
+	name := err.StructField()
+	if i := strings.Index(name, "["); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
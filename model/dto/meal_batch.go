@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageBatchOperations string = "Please enter 1 to 100 operations."
+	ValidationErrMessageBatchMode       string = "Please enter the mode with atomic or best_effort."
+	ValidationErrMessageBatchOp         string = "Please enter the operation with create, update or delete."
+	ValidationErrMessageBatchMeal       string = "Please enter the meal data to create or update."
+	ValidationErrMessageBatchID         string = "Please enter the ID of the meal to update or delete."
+)
+
+// MealBatchDto defines a data transfer object for the operations on the Meals in a batch.
+// The mode is atomic, which applies all operations or none of them, unless best_effort is given.
+type MealBatchDto struct {
+	mode       string                  `json:"mode"`
+	operations []MealBatchOperationDto `validate:"required,min=1,max=100" json:"operations"`
+}
+
+// MealBatchOperationDto defines a data transfer object for an operation in a batch.
+// The id is required to update or delete a Meal, and the meal is required to create or update one.
+type MealBatchOperationDto struct {
+	op   string   `validate:"required,oneof=create update delete" json:"op"`
+	id   uint     `json:"id"`
+	meal *MealDto `validate:"-" json:"meal"`
+}
+
+// NewMealBatchDto is constructor.
+func NewMealBatchDto() *MealBatchDto {
+	return &MealBatchDto{}
+}
+
+// Mode returns the mode of the batch, atomic or best_effort.
+func (m *MealBatchDto) Mode() string {
+	if m.mode == "" {
+		return model.MealBatchAtomic
+	}
+	return m.mode
+}
+
+// Operations returns the operations of the batch in order.
+func (m *MealBatchDto) Operations() []MealBatchOperationDto {
+	return m.operations
+}
+
+// Ops returns the kinds of the operations of the batch in order.
+func (m *MealBatchDto) Ops() []string {
+	ops := make([]string, len(m.operations))
+	for i := range m.operations {
+		ops[i] = m.operations[i].op
+	}
+	return ops
+}
+
+// Validate performs validation check for the mode and the number of the operations.
+// The each operation is validated by its own Validate so that the errors are reported by the index.
+func (m *MealBatchDto) Validate() map[string]string {
+	result := validateDto(m)
+	if m.mode != "" && m.mode != model.MealBatchAtomic && m.mode != model.MealBatchBestEffort {
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result["mode"] = ValidationErrMessageBatchMode
+	}
+	return result
+}
+
+// Op returns the kind of the operation, create, update or delete.
+func (o *MealBatchOperationDto) Op() string {
+	return o.op
+}
+
+// ID returns the ID of the Meal to update or delete.
+func (o *MealBatchOperationDto) ID() string {
+	return strconv.FormatUint(uint64(o.id), 10)
+}
+
+// Meal returns the Meal data to create or update.
+func (o *MealBatchOperationDto) Meal() *MealDto {
+	return o.meal
+}
+
+// Validate performs validation check for the kind of the operation, the ID and the Meal data which it needs.
+func (o *MealBatchOperationDto) Validate() map[string]string {
+	result := validateDto(o)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	if o.op == model.MealBatchUpdate || o.op == model.MealBatchDelete {
+		if o.id == 0 {
+			result["id"] = ValidationErrMessageBatchID
+		}
+	}
+	if o.op == model.MealBatchCreate || o.op == model.MealBatchUpdate {
+		if o.meal == nil {
+			result["meal"] = ValidationErrMessageBatchMeal
+		} else {
+			for field, message := range o.meal.Validate() {
+				result[field] = message
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// ToString is return string of object
+func (m *MealBatchDto) ToString() (string, error) {
+	bytes, err := json.Marshal(m)
+	return string(bytes), err
+}
@@ -0,0 +1,192 @@
+package dto
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	ValidationErrMessageUnknownFilter string = "This filter is not supported. Please use query, from, to, food_id, meal_type, min_calories, max_calories, sort, cursor, page or size."
+	ValidationErrMessageMealSort      string = "Please enter the fields to sort by with id, meal_name, meal_at, meal_type or calories separated by commas. Prefix - sorts in descending order."
+	ValidationErrMessageCalories      string = "Please enter the calories 0 or greater."
+	ValidationErrMessageCalorieRange  string = "Please enter max_calories greater than or equal to min_calories."
+	ValidationErrMessageNumber        string = "Please enter the number."
+	ValidationErrMessageCursor        string = "This cursor is invalid. Please use the next or prev cursor of the previous response, or an empty cursor for the first page."
+	ValidationErrMessageCursorSort    string = "The Meals cannot be sorted with the cursor. They are always the newest first."
+	ValidationErrMessageCursorPage    string = "The page cannot be used with the cursor. Please use either of them."
+)
+
+// mealFilterParams are the query parameters which MealFilterDto accepts.
+var mealFilterParams = []string{"query", "from", "to", "food_id", "meal_type", "min_calories", "max_calories", "sort", "cursor", "page", "size"}
+
+// MealFilterDto defines a data transfer object for the filters and the order of the Meal list.
+// It is made from the query parameters and the unknown parameters are reported by Validate.
+// The Meals are paged by the cursor instead of the page number if the cursor parameter is given, even if it is empty.
+type MealFilterDto struct {
+	query        string   `json:"query"`
+	from         string   `validate:"omitempty,datetime=2006-01-02" json:"from"`
+	to           string   `validate:"omitempty,datetime=2006-01-02" json:"to"`
+	food_id      string   `validate:"omitempty,numeric" json:"food_id"`
+	meal_type    string   `validate:"omitempty,oneof=breakfast lunch dinner snack" json:"meal_type"`
+	min_calories string   `validate:"omitempty,numeric" json:"min_calories"`
+	max_calories string   `validate:"omitempty,numeric" json:"max_calories"`
+	sort         string   `json:"sort"`
+	cursor       string   `json:"cursor"`
+	by_cursor    bool     `json:"-"`
+	page         string   `validate:"omitempty,numeric" json:"page"`
+	size         string   `validate:"omitempty,numeric" json:"size"`
+	unknown      []string `json:"-"`
+}
+
+// NewMealFilterDto is constructor. It reads the filters from given query parameters.
+func NewMealFilterDto(params url.Values) *MealFilterDto {
+	m := &MealFilterDto{query: params.Get("query"), from: params.Get("from"), to: params.Get("to"),
+		food_id: params.Get("food_id"), meal_type: params.Get("meal_type"), min_calories: params.Get("min_calories"),
+		max_calories: params.Get("max_calories"), sort: params.Get("sort"), page: params.Get("page"), size: params.Get("size")}
+	m.cursor, m.by_cursor = params.Get("cursor"), params.Has("cursor")
+	for name := range params {
+		known := false
+		for _, param := range mealFilterParams {
+			known = known || param == name
+		}
+		if !known {
+			m.unknown = append(m.unknown, name)
+		}
+	}
+	sort.Strings(m.unknown)
+	return m
+}
+
+// Page returns the page number.
+func (m *MealFilterDto) Page() string {
+	return m.page
+}
+
+// Size returns the number of the Meals per page.
+func (m *MealFilterDto) Size() string {
+	return m.size
+}
+
+// IsCursor returns true if the Meals are paged by the cursor.
+func (m *MealFilterDto) IsCursor() bool {
+	return m.by_cursor
+}
+
+// Cursor returns the cursor of the page to fetch, or nil for the first page or the invalid cursor.
+func (m *MealFilterDto) Cursor() *model.MealCursor {
+	if m.cursor == "" {
+		return nil
+	}
+	cursor, err := model.DecodeMealCursor(m.cursor)
+	if err != nil {
+		return nil
+	}
+	return cursor
+}
+
+// Create creates a MealFilter model of a given user from this DTO. The days are in given time zone of the user.
+func (m *MealFilterDto) Create(user *model.User, loc *time.Location) *model.MealFilter {
+	filter := model.NewMealFilter(user).Name(m.query).MealType(m.meal_type)
+
+	var from, to *time.Time
+	if m.from != "" {
+		day, _ := util.ParseDateIn(m.from, loc)
+		from = &day
+	}
+	if m.to != "" {
+		day, _ := util.ParseDateIn(m.to, loc)
+		next := day.AddDate(0, 0, 1)
+		to = &next
+	}
+	filter.Range(from, to)
+
+	if m.food_id != "" {
+		filter.Food(util.ConvertToUint(m.food_id))
+	}
+	filter.Calories(parseFloat(m.min_calories), parseFloat(m.max_calories))
+	for _, field := range m.sortFields() {
+		_ = filter.SortBy(strings.TrimPrefix(field, "-"), strings.HasPrefix(field, "-"))
+	}
+	return filter
+}
+
+// Validate performs validation check for the each item, the unknown parameters and the fields to sort by.
+func (m *MealFilterDto) Validate() map[string]string {
+	result := validateDto(m)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for _, name := range m.unknown {
+		result[name] = ValidationErrMessageUnknownFilter
+	}
+	for _, field := range m.sortFields() {
+		if _, ok := model.MealSortFields[strings.TrimPrefix(field, "-")]; !ok {
+			result["sort"] = ValidationErrMessageMealSort
+		}
+	}
+	lower, upper := parseFloat(m.min_calories), parseFloat(m.max_calories)
+	if lower != nil && *lower < 0 {
+		result["min_calories"] = ValidationErrMessageCalories
+	}
+	if upper != nil && *upper < 0 {
+		result["max_calories"] = ValidationErrMessageCalories
+	}
+	if _, ok := result["max_calories"]; !ok && lower != nil && upper != nil && *lower > *upper {
+		result["max_calories"] = ValidationErrMessageCalorieRange
+	}
+	if _, ok := result["to"]; !ok && m.from != "" && m.to != "" && m.from > m.to {
+		result["to"] = ValidationErrMessageDateRange
+	}
+	if m.by_cursor {
+		if _, err := model.DecodeMealCursor(m.cursor); m.cursor != "" && err != nil {
+			result["cursor"] = ValidationErrMessageCursor
+		}
+		if m.sort != "" {
+			result["sort"] = ValidationErrMessageCursorSort
+		}
+		if _, ok := result["page"]; !ok && m.page != "" {
+			result["page"] = ValidationErrMessageCursorPage
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// sortFields returns the fields to sort by, which may be prefixed with + or -. The prefix + is removed.
+func (m *MealFilterDto) sortFields() []string {
+	var fields []string
+	if m.sort == "" {
+		return fields
+	}
+	for _, field := range strings.Split(m.sort, ",") {
+		fields = append(fields, strings.TrimPrefix(strings.TrimSpace(field), "+"))
+	}
+	return fields
+}
+
+// parseFloat returns the number of given string, or nil if it is empty or not a number.
+func parseFloat(value string) *float64 {
+	if value == "" {
+		return nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
+
+// ToString is return string of object
+func (m *MealFilterDto) ToString() (string, error) {
+	bytes, err := json.Marshal(m)
+	return string(bytes), err
+}
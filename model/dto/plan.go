@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessagePlanItems string = "Please enter at least one food item."
+)
+
+// PlannedMealDto defines a data transfer object for PlannedMeal.
+type PlannedMealDto struct {
+	meal_name  string        `validate:"required" json:"meal_name"`
+	planned_at time.Time     `validate:"required" json:"planned_at"`
+	items      []MealItemDto `validate:"required,min=1,dive" json:"items"`
+}
+
+// NewPlannedMealDto is constructor.
+func NewPlannedMealDto() *PlannedMealDto {
+	return &PlannedMealDto{}
+}
+
+// Create creates a PlannedMeal model of a given user from this DTO.
+func (p *PlannedMealDto) Create(user *model.User) *model.PlannedMeal {
+	items := make([]model.MealItem, len(p.items))
+	for i := range p.items {
+		if p.items[i].food_id == 0 {
+			items[i] = *model.NewScannedMealItem(p.items[i].barcode, p.items[i].quantity, p.items[i].unit)
+			continue
+		}
+		items[i] = *model.NewMealItem(p.items[i].food_id, p.items[i].quantity, p.items[i].unit)
+	}
+	return model.NewPlannedMeal(user, p.meal_name, p.planned_at, items)
+}
+
+// Validate performs validation check for the each item.
+func (p *PlannedMealDto) Validate() map[string]string {
+	return validateDto(p)
+}
+
+// ToString is return string of object
+func (p *PlannedMealDto) ToString() (string, error) {
+	bytes, err := json.Marshal(p)
+	return string(bytes), err
+}
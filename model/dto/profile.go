@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	ValidationErrMessageHeight        string = "Please enter the height in cm greater than 0."
+	ValidationErrMessageSex           string = "Please enter the sex with male or female."
+	ValidationErrMessageActivityLevel string = "Please enter the activity level with sedentary, light, moderate, active or very_active."
+	ValidationErrMessageTimezone      string = "Please enter the time zone with the IANA name such as Asia/Tokyo."
+)
+
+// ProfileDto defines a data transfer object for the body profile of a user.
+type ProfileDto struct {
+	height         float64 `validate:"required,gt=0" json:"height"`
+	birth_date     string  `validate:"required,datetime=2006-01-02" json:"birth_date"`
+	sex            string  `validate:"required,oneof=male female" json:"sex"`
+	activity_level string  `validate:"required,oneof=sedentary light moderate active very_active" json:"activity_level"`
+	timezone       string  `validate:"omitempty,timezone" json:"timezone"`
+}
+
+// NewProfileDto is constructor.
+func NewProfileDto() *ProfileDto {
+	return &ProfileDto{}
+}
+
+// Height returns the height in cm.
+func (p *ProfileDto) Height() float64 {
+	return p.height
+}
+
+// BirthDate returns the birth date.
+func (p *ProfileDto) BirthDate() time.Time {
+	date, _ := util.ParseDate(p.birth_date)
+	return date
+}
+
+// Sex returns the sex.
+func (p *ProfileDto) Sex() string {
+	return p.sex
+}
+
+// ActivityLevel returns the activity level.
+func (p *ProfileDto) ActivityLevel() string {
+	return p.activity_level
+}
+
+// Timezone returns the IANA time zone. It is empty if the time zone is not changed.
+func (p *ProfileDto) Timezone() string {
+	return p.timezone
+}
+
+// Validate performs validation check for the each item.
+func (p *ProfileDto) Validate() map[string]string {
+	return validateDto(p)
+}
+
+// ToString is return string of object
+func (p *ProfileDto) ToString() (string, error) {
+	bytes, err := json.Marshal(p)
+	return string(bytes), err
+}
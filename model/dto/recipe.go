@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageRecipeName        string = "Please enter the name with 1 to 100 characters."
+	ValidationErrMessageServings          string = "Please enter the servings greater than 0."
+	ValidationErrMessageRecipeIngredients string = "Please enter at least one ingredient."
+)
+
+// RecipeDto defines a data transfer object for Recipe.
+type RecipeDto struct {
+	recipe_name string        `validate:"required,max=100" json:"recipe_name"`
+	servings    float64       `validate:"required,gt=0" json:"servings"`
+	ingredients []MealItemDto `validate:"required,min=1,dive" json:"ingredients"`
+}
+
+// NewRecipeDto is constructor.
+func NewRecipeDto() *RecipeDto {
+	return &RecipeDto{}
+}
+
+// Create creates a Recipe model of a given user from this DTO.
+func (r *RecipeDto) Create(user *model.User) *model.Recipe {
+	ingredients := make([]model.RecipeIngredient, len(r.ingredients))
+	for i := range r.ingredients {
+		ingredients[i] = *model.NewRecipeIngredient(r.ingredients[i].food_id, r.ingredients[i].quantity, r.ingredients[i].unit)
+	}
+	return model.NewRecipe(user, r.recipe_name, r.servings, ingredients)
+}
+
+// Validate performs validation check for the each item.
+func (r *RecipeDto) Validate() map[string]string {
+	return validateDto(r)
+}
+
+// ToString is return string of object
+func (r *RecipeDto) ToString() (string, error) {
+	bytes, err := json.Marshal(r)
+	return string(bytes), err
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageAllergens       string = "Please enter the allergens with gluten, nuts, peanuts, dairy, egg, soy, fish, shellfish or sesame."
+	ValidationErrMessageDiets           string = "Please enter the diets with vegan, vegetarian, halal, kosher or keto."
+	ValidationErrMessageRestrictionMode string = "Please enter the mode with warn or reject."
+)
+
+// RestrictionDto defines a data transfer object for the dietary restrictions of a user.
+type RestrictionDto struct {
+	mode      string   `validate:"omitempty,oneof=warn reject" json:"mode"`
+	allergens []string `validate:"dive,oneof=gluten nuts peanuts dairy egg soy fish shellfish sesame" json:"allergens"`
+	diets     []string `validate:"dive,oneof=vegan vegetarian halal kosher keto" json:"diets"`
+}
+
+// FoodTagDto defines a data transfer object for the allergen and the diet tags of a Food.
+type FoodTagDto struct {
+	allergens []string `validate:"dive,oneof=gluten nuts peanuts dairy egg soy fish shellfish sesame" json:"allergens"`
+	diets     []string `validate:"dive,oneof=vegan vegetarian halal kosher keto" json:"diets"`
+}
+
+// NewRestrictionDto is constructor.
+func NewRestrictionDto() *RestrictionDto {
+	return &RestrictionDto{}
+}
+
+// NewFoodTagDto is constructor.
+func NewFoodTagDto() *FoodTagDto {
+	return &FoodTagDto{}
+}
+
+// Mode returns whether the conflicting meals are warned or rejected. It is warn if omitted.
+func (r *RestrictionDto) Mode() string {
+	if r.mode == "" {
+		return model.RestrictionModeWarn
+	}
+	return r.mode
+}
+
+// Allergens returns the allergens to avoid.
+func (r *RestrictionDto) Allergens() []string {
+	return uniqueTags(r.allergens)
+}
+
+// Diets returns the diets to follow.
+func (r *RestrictionDto) Diets() []string {
+	return uniqueTags(r.diets)
+}
+
+// Validate performs validation check for the each item.
+func (r *RestrictionDto) Validate() map[string]string {
+	return validateDto(r)
+}
+
+// ToString is return string of object
+func (r *RestrictionDto) ToString() (string, error) {
+	bytes, err := json.Marshal(r)
+	return string(bytes), err
+}
+
+// Allergens returns the allergens which the food contains.
+func (t *FoodTagDto) Allergens() []string {
+	return uniqueTags(t.allergens)
+}
+
+// Diets returns the diets which the food is suitable for.
+func (t *FoodTagDto) Diets() []string {
+	return uniqueTags(t.diets)
+}
+
+// Validate performs validation check for the each item.
+func (t *FoodTagDto) Validate() map[string]string {
+	return validateDto(t)
+}
+
+// ToString is return string of object
+func (t *FoodTagDto) ToString() (string, error) {
+	bytes, err := json.Marshal(t)
+	return string(bytes), err
+}
+
+func uniqueTags(tags []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	ValidationErrMessageShoppingListName string = "Please enter the name with 1 to 100 characters."
+)
+
+// ShoppingListDto defines a data transfer object for generating a ShoppingList
+// from the planned Meals from a day to a day and the Recipes to cook.
+type ShoppingListDto struct {
+	list_name string          `validate:"required,max=100" json:"list_name"`
+	from      string          `validate:"required,datetime=2006-01-02" json:"from"`
+	to        string          `validate:"required,datetime=2006-01-02" json:"to"`
+	recipes   []MealRecipeDto `validate:"dive" json:"recipes"`
+}
+
+// ShoppingListItemDto defines a data transfer object for checking an item of a ShoppingList.
+type ShoppingListItemDto struct {
+	checked bool `json:"checked"`
+}
+
+// NewShoppingListDto is constructor.
+func NewShoppingListDto() *ShoppingListDto {
+	return &ShoppingListDto{}
+}
+
+// NewShoppingListItemDto is constructor.
+func NewShoppingListItemDto() *ShoppingListItemDto {
+	return &ShoppingListItemDto{}
+}
+
+// Create creates a ShoppingList model of a given user from this DTO. The days are in given time zone of the user.
+func (s *ShoppingListDto) Create(user *model.User, loc *time.Location) *model.ShoppingList {
+	return model.NewShoppingList(user, s.list_name, s.From(loc), s.To(loc))
+}
+
+// From returns the first day of the planned Meals in given time zone.
+func (s *ShoppingListDto) From(loc *time.Location) time.Time {
+	from, _ := util.ParseDateIn(s.from, loc)
+	return from
+}
+
+// To returns the last day of the planned Meals in given time zone.
+func (s *ShoppingListDto) To(loc *time.Location) time.Time {
+	to, _ := util.ParseDateIn(s.to, loc)
+	return to
+}
+
+// Recipes returns the Recipes to cook and their servings.
+func (s *ShoppingListDto) Recipes() []model.MealRecipe {
+	recipes := make([]model.MealRecipe, len(s.recipes))
+	for i := range s.recipes {
+		recipes[i] = *model.NewMealRecipe(s.recipes[i].recipe_id, s.recipes[i].servings)
+	}
+	return recipes
+}
+
+// Validate performs validation check for the each item.
+func (s *ShoppingListDto) Validate() map[string]string {
+	result := validateDto(s)
+	if result == nil && s.From(time.UTC).After(s.To(time.UTC)) {
+		result = map[string]string{"to": ValidationErrMessageDateRange}
+	}
+	return result
+}
+
+// Checked returns true if the item is bought.
+func (s *ShoppingListItemDto) Checked() bool {
+	return s.checked
+}
+
+// ToString is return string of object
+func (s *ShoppingListDto) ToString() (string, error) {
+	bytes, err := json.Marshal(s)
+	return string(bytes), err
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	ValidationErrMessageTemplateName   string = "Please enter the name with 1 to 100 characters."
+	ValidationErrMessageTemplateSource string = "Please enter the meal ID or the date."
+)
+
+// MealTemplateDto defines a data transfer object for MealTemplate.
+// The template is saved from the Meal of meal_id, or from all Meals of the date if meal_id is omitted.
+type MealTemplateDto struct {
+	template_name string `validate:"required,max=100" json:"template_name"`
+	favorite      bool   `json:"favorite"`
+	meal_id       uint   `validate:"required_without=date" json:"meal_id"`
+	date          string `validate:"omitempty,datetime=2006-01-02" json:"date"`
+}
+
+// NewMealTemplateDto is constructor.
+func NewMealTemplateDto() *MealTemplateDto {
+	return &MealTemplateDto{}
+}
+
+// Create creates a MealTemplate model of a given user from this DTO.
+func (t *MealTemplateDto) Create(user *model.User) *model.MealTemplate {
+	return model.NewMealTemplate(user, t.template_name, t.favorite)
+}
+
+// MealID returns the ID of the Meal which the template is saved from. It is 0 if the date is used.
+func (t *MealTemplateDto) MealID() uint {
+	return t.meal_id
+}
+
+// Date returns the day whose Meals the template is saved from in given time zone.
+func (t *MealTemplateDto) Date(loc *time.Location) time.Time {
+	date, _ := util.ParseDateIn(t.date, loc)
+	return date
+}
+
+// Validate performs validation check for the each item.
+func (t *MealTemplateDto) Validate() map[string]string {
+	return validateDto(t)
+}
+
+// ToString is return string of object
+func (t *MealTemplateDto) ToString() (string, error) {
+	bytes, err := json.Marshal(t)
+	return string(bytes), err
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+)
+
+const (
+	ValidationErrMessageWeight string = "Please enter the weight in kg greater than 0."
+)
+
+// WeightDto defines a data transfer object for WeightEntry.
+type WeightDto struct {
+	weight      float64   `validate:"required,gt=0" json:"weight"`
+	measured_at time.Time `validate:"required" json:"measured_at"`
+}
+
+// NewWeightDto is constructor.
+func NewWeightDto() *WeightDto {
+	return &WeightDto{}
+}
+
+// Create creates a WeightEntry model of a given user from this DTO.
+func (w *WeightDto) Create(user *model.User) *model.WeightEntry {
+	return model.NewWeightEntry(user, w.weight, w.measured_at)
+}
+
+// Weight returns the weight in kg.
+func (w *WeightDto) Weight() float64 {
+	return w.weight
+}
+
+// MeasuredAt returns the measured time.
+func (w *WeightDto) MeasuredAt() time.Time {
+	return w.measured_at
+}
+
+// Validate performs validation check for the each item.
+func (w *WeightDto) Validate() map[string]string {
+	return validateDto(w)
+}
+
+// ToString is return string of object
+func (w *WeightDto) ToString() (string, error) {
+	bytes, err := json.Marshal(w)
+	return string(bytes), err
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+const (
+	// SexMale represents male.
+	SexMale = "male"
+	// SexFemale represents female.
+	SexFemale = "female"
+)
+
+// activityFactors are the multipliers of BMR to estimate TDEE by the activity level.
+var activityFactors = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// Energy defines struct of the body indexes and the estimated energy expenditure of a user in a day.
+type Energy struct {
+	weight float64 `json:"weight"`
+	bmi    float64 `json:"bmi"`
+	bmr    float64 `json:"bmr"`
+	tdee   float64 `json:"tdee"`
+}
+
+// Profile defines struct of the body profile of a user and the energy computed from it.
+type Profile struct {
+	user   *User   `json:"user"`
+	energy *Energy `json:"energy"`
+}
+
+// FindByUserAndDate returns the Energy of a given user in a given day.
+// It is computed from the latest weight until the day and the body profile of the user.
+// If the weight or the profile is not registered, it returns None.
+func (e *Energy) FindByUserAndDate(rep repository.Repository, user *User, date time.Time) optional.Option[*Energy] {
+	u, err := user.FindByID(rep, user.user_id).Take()
+	if err != nil || !u.hasProfile() {
+		return optional.None[*Energy]()
+	}
+
+	entry := WeightEntry{}
+	w, err := entry.FindLatestByUserAndDate(rep, u, date).Take()
+	if err != nil {
+		return optional.None[*Energy]()
+	}
+	return optional.Some(computeEnergy(u, w.weight, date))
+}
+
+// FindByUser returns the Profile of a given user with the Energy of today in the time zone of the user.
+func (p *Profile) FindByUser(rep repository.Repository, user *User) (*Profile, error) {
+	u, err := user.FindByID(rep, user.user_id).Take()
+	if err != nil {
+		return nil, err
+	}
+	energy := Energy{}
+	result := &Profile{user: u}
+	if e, err := energy.FindByUserAndDate(rep, u, time.Now().In(u.Location(rep))).Take(); err == nil {
+		result.energy = e
+	}
+	return result, nil
+}
+
+// computeEnergy computes BMI, BMR by the Mifflin-St Jeor equation and TDEE.
+func computeEnergy(user *User, weight float64, date time.Time) *Energy {
+	height := user.height / 100
+	bmr := 10*weight + 6.25*user.height - 5*float64(user.ageAt(date))
+	if user.sex == SexMale {
+		bmr += 5
+	} else {
+		bmr -= 161
+	}
+
+	factor, ok := activityFactors[user.activity_level]
+	if !ok {
+		factor = activityFactors["sedentary"]
+	}
+	return &Energy{weight: weight, bmi: weight / (height * height), bmr: bmr, tdee: bmr * factor}
+}
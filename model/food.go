@@ -1,25 +1,127 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/moznion/go-optional"
 	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
 )
 
-// Food defines struct of Food data.
+// Food defines struct of Food data. The amounts of nutrients are per 100 g.
+// The Food of the global catalog has no user_id, and the private Food of a user is visible only to the user.
+// The category and the purchase unit such as a bottle of purchase_size grams are used for the shopping list.
+// The serving size and the piece size in grams and the density in grams per milliliter convert the portions to grams.
+// The Food which has deleted_at is soft-deleted. It is hidden from the catalog but the Meals logged with it keep referring to it.
 type Food struct {
-	food_id     uint    `gorm:"primary_key" json:"id"`
-	food_name   string  `validate:"required" json:"food_name"`
-	calo_amount float64 `validate:"required" json:"calo_amount"`
+	food_id        uint               `gorm:"primary_key" json:"id"`
+	user_id        *uint              `gorm:"index" json:"user_id"`
+	food_name      string             `validate:"required" json:"food_name"`
+	calo_amount    float64            `validate:"required" json:"calo_amount"`
+	protein        float64            `json:"protein"`
+	carbohydrate   float64            `json:"carbohydrate"`
+	fat            float64            `json:"fat"`
+	fiber          float64            `json:"fiber"`
+	sugar          float64            `json:"sugar"`
+	sodium         float64            `json:"sodium"`
+	source         string             `json:"source"`
+	source_id      string             `json:"source_id"`
+	barcode        string             `gorm:"index" json:"barcode"`
+	category       string             `json:"category"`
+	purchase_unit  string             `json:"purchase_unit"`
+	purchase_size  float64            `json:"purchase_size"`
+	serving_size   float64            `json:"serving_size"`
+	piece_size     float64            `json:"piece_size"`
+	density        float64            `json:"density"`
+	deleted_at     *time.Time         `gorm:"index" json:"deleted_at,omitempty"`
+	micronutrients map[string]float64 `gorm:"-" json:"micronutrients"`
+	allergens      []string           `gorm:"-" json:"allergens"`
+	diets          []string           `gorm:"-" json:"diets"`
 }
 
+// foodColumns are the columns of the foods table which hold the nutrients.
+var foodColumns = []string{"food_name", "calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium", "source", "source_id", "barcode"}
+
+// editableFoodColumns are the columns of the foods table which a user edits.
+var editableFoodColumns = []string{"food_name", "calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium",
+	"serving_size", "piece_size", "density"}
+
+// ErrMergeSameFood is returned when a Food is merged into itself.
+var ErrMergeSameFood = errors.New("the food cannot be merged into itself")
+
+// foodReferences are the tables which refer to a Food by food_id.
+// A Food is soft-deleted while any of them refers to it, and they are re-pointed when the Food is merged.
+var foodReferences = []string{"meal_items", "recipe_ingredients", "planned_meal_items", "meal_template_items",
+	"hydration_entries", "shopping_list_items"}
+
+const (
+	findVisibleFoods = "deleted_at is null and (user_id is null or user_id = ?)"
+	// countFoodReference is the SQL which counts the rows of a table of foodReferences which refer to a Food.
+	countFoodReference = "(select count(*) from %s where food_id = ?)"
+)
+
 // TableName returns the table name of Food struct and it is used by gorm.
 func (Food) TableName() string {
 	return "foods"
 }
 
 // NewFood is constructor
-func NewFood(food_name string) *Food {
-	return &Food{food_name: food_name}
+func NewFood(food_name string, nutrients *Nutrients) *Food {
+	return &Food{food_name: food_name, calo_amount: nutrients.calories,
+		protein: nutrients.protein, carbohydrate: nutrients.carbohydrate, fat: nutrients.fat,
+		fiber: nutrients.fiber, sugar: nutrients.sugar, sodium: nutrients.sodium,
+		micronutrients: nutrients.micronutrients}
+}
+
+// NewUserFood is constructor of the private Food of a given user.
+func NewUserFood(user *User, food_name string, nutrients *Nutrients) *Food {
+	f := NewFood(food_name, nutrients)
+	f.user_id = &user.user_id
+	return f
+}
+
+// NewImportedFood is constructor of the Food imported from a food database such as USDA FoodData Central.
+func NewImportedFood(food_name string, source string, source_id string, nutrients *Nutrients) *Food {
+	f := NewFood(food_name, nutrients)
+	f.source = source
+	f.source_id = source_id
+	return f
+}
+
+// NewPackagedFood is constructor of the packaged Food which has an EAN-13 barcode.
+func NewPackagedFood(food_name string, barcode string, source string, source_id string, nutrients *Nutrients) *Food {
+	f := NewImportedFood(food_name, source, source_id, nutrients)
+	f.barcode = barcode
+	return f
+}
+
+// SetPurchase sets the category and the unit in which this Food is bought. The size is the grams of the unit.
+func (f *Food) SetPurchase(category string, purchase_unit string, purchase_size float64) *Food {
+	f.category = category
+	f.purchase_unit = purchase_unit
+	f.purchase_size = purchase_size
+	return f
+}
+
+// SetSizes sets the grams of a serving and a piece and the grams per milliliter of this Food.
+// Zero means that the size is undefined.
+func (f *Food) SetSizes(serving_size float64, piece_size float64, density float64) *Food {
+	f.serving_size = serving_size
+	f.piece_size = piece_size
+	f.density = density
+	return f
+}
+
+// UpdateSizes updates the grams of a serving and a piece and the grams per milliliter of this Food.
+func (f *Food) UpdateSizes(rep repository.Repository, serving_size float64, piece_size float64, density float64) (*Food, error) {
+	f.SetSizes(serving_size, piece_size, density)
+	if err := rep.Model(f).Select("serving_size", "piece_size", "density").Updates(f).Error; err != nil {
+		return nil, err
+	}
+	return f, nil
 }
 
 // Exist returns true if a given Food exits.
@@ -36,30 +138,361 @@ func (f *Food) Exist(rep repository.Repository, food_id uint) (bool, error) {
 
 // FindByID returns a Food full matched given Food's ID.
 func (f *Food) FindByID(rep repository.Repository, food_id uint) optional.Option[*Food] {
-	var Food Food
-	if err := rep.Where("id = ?", foods).First(&Food).Error; err != nil {
+	var food Food
+	if err := rep.Where("food_id = ?", food_id).First(&food).Error; err != nil {
+		return optional.None[*Food]()
+	}
+
+	foods := []Food{food}
+	if err := loadFoodDetails(rep, foods); err != nil {
+		return optional.None[*Food]()
+	}
+	return optional.Some(&foods[0])
+}
+
+// FindVisibleByID returns a Food full matched given Food's ID if it is in the global catalog or belongs to a given user.
+func (f *Food) FindVisibleByID(rep repository.Repository, user *User, food_id uint) optional.Option[*Food] {
+	food, err := f.FindByID(rep, food_id).Take()
+	if err != nil || !food.IsVisibleTo(user) {
 		return optional.None[*Food]()
 	}
-	return optional.Some(&Food)
+	return optional.Some(food)
 }
 
-// FindAll returns all categories of the Food table.
-func (f *Food) FindAll(rep repository.Repository) (*[]Food, error) {
-	var categories []Food
-	if err := rep.Find(&foods).Error; err != nil {
+// FindByBarcode returns a Food of the global catalog matched given EAN-13 barcode.
+func (f *Food) FindByBarcode(rep repository.Repository, barcode string) optional.Option[*Food] {
+	var food Food
+	if err := rep.Where("barcode = ? and user_id is null and deleted_at is null", barcode).First(&food).Error; err != nil {
+		return optional.None[*Food]()
+	}
+	return f.FindByID(rep, food.food_id)
+}
+
+// FindByUser returns the Foods of the global catalog and the private Foods of a given user.
+// Only the global catalog is returned if the user is nil.
+func (f *Food) FindByUser(rep repository.Repository, user *User) (*[]Food, error) {
+	user_id := uint(0)
+	if user != nil {
+		user_id = user.user_id
+	}
+
+	var foods []Food
+	if err := rep.Where(findVisibleFoods, user_id).Order("food_name").Find(&foods).Error; err != nil {
 		return nil, err
 	}
-	return &categories, nil
+
+	if err := loadFoodDetails(rep, foods); err != nil {
+		return nil, err
+	}
+	return &foods, nil
+}
+
+// loadFoodDetails fetches the micronutrients and the tags of given Foods.
+func loadFoodDetails(rep repository.Repository, foods []Food) error {
+	ids := make([]uint, len(foods))
+	for i := range foods {
+		ids[i] = foods[i].food_id
+	}
+
+	nutrient := FoodNutrient{}
+	micronutrients, err := nutrient.FindByFoodIDs(rep, ids)
+	if err != nil {
+		return err
+	}
+	tag := FoodTag{}
+	tags, err := tag.FindByFoodIDs(rep, ids)
+	if err != nil {
+		return err
+	}
+	for i := range foods {
+		foods[i].setMicronutrients(micronutrients[foods[i].food_id])
+		foods[i].setTags(tags[foods[i].food_id])
+	}
+	return nil
 }
 
-// Create persists this Food data.
+// Create persists this Food data and its micronutrients.
 func (f *Food) Create(rep repository.Repository) (*Food, error) {
 	if err := rep.Create(f).Error; err != nil {
 		return nil, err
 	}
+	invalidateFoodIndex()
+	for name, amount := range f.micronutrients {
+		if _, err := NewFoodNutrient(f.food_id, name, amount).Create(rep); err != nil {
+			return nil, err
+		}
+	}
+	if err := f.createTags(rep); err != nil {
+		return nil, err
+	}
+	f.setTags(&foodTags{allergens: f.allergens, diets: f.diets})
+	return f, nil
+}
+
+// Update updates the name, the nutrients, the source and the barcode of this Food by given Food.
+func (f *Food) Update(rep repository.Repository, food *Food) (*Food, error) {
+	food.food_id = f.food_id
+	if err := rep.Model(f).Select(foodColumns).Updates(food).Error; err != nil {
+		return nil, err
+	}
+	invalidateFoodIndex()
+	if err := rep.Where("food_id = ?", f.food_id).Delete(&FoodNutrient{}).Error; err != nil {
+		return nil, err
+	}
+	for name, amount := range food.micronutrients {
+		if _, err := NewFoodNutrient(f.food_id, name, amount).Create(rep); err != nil {
+			return nil, err
+		}
+	}
+	if food.allergens == nil && food.diets == nil {
+		food.allergens, food.diets = f.allergens, f.diets
+	} else if _, err := f.SetTags(rep, food.allergens, food.diets); err != nil {
+		return nil, err
+	}
+	*f = *food
+	return f, nil
+}
+
+// Edit updates the name, the nutrients, the micronutrients, the sizes and the tags of this Food by given Food.
+// The source, the barcode and the owner are not changed. The tags are kept if given Food has no tags of either kind.
+func (f *Food) Edit(rep repository.Repository, food *Food) (*Food, error) {
+	if err := rep.Model(f).Select(editableFoodColumns).Updates(food).Error; err != nil {
+		return nil, err
+	}
+	invalidateFoodIndex()
+	if err := rep.Where("food_id = ?", f.food_id).Delete(&FoodNutrient{}).Error; err != nil {
+		return nil, err
+	}
+	for name, amount := range food.micronutrients {
+		if _, err := NewFoodNutrient(f.food_id, name, amount).Create(rep); err != nil {
+			return nil, err
+		}
+	}
+	if food.allergens != nil || food.diets != nil {
+		if _, err := f.SetTags(rep, food.allergens, food.diets); err != nil {
+			return nil, err
+		}
+	}
+	f.food_name = food.food_name
+	f.calo_amount, f.protein, f.carbohydrate, f.fat = food.calo_amount, food.protein, food.carbohydrate, food.fat
+	f.fiber, f.sugar, f.sodium = food.fiber, food.sugar, food.sodium
+	f.SetSizes(food.serving_size, food.piece_size, food.density)
+	f.setMicronutrients(food.micronutrients)
+	return f, nil
+}
+
+// Delete deletes this Food data with its micronutrients and tags.
+// If any table of foodReferences refers to this Food, it is soft-deleted instead so that the references are not broken.
+func (f *Food) Delete(rep repository.Repository) (*Food, error) {
+	counts := make([]string, len(foodReferences))
+	args := make([]interface{}, len(foodReferences))
+	for i, table := range foodReferences {
+		counts[i] = fmt.Sprintf(countFoodReference, table)
+		args[i] = f.food_id
+	}
+
+	var count int64
+	if err := rep.Raw("select "+strings.Join(counts, " + ")+" as count", args...).Row().Scan(&count); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		now := time.Now()
+		f.deleted_at = &now
+		if err := rep.Model(f).Select("deleted_at").Updates(f).Error; err != nil {
+			return nil, err
+		}
+		invalidateFoodIndex()
+		return f, nil
+	}
+
+	if err := rep.Where("food_id = ?", f.food_id).Delete(&FoodNutrient{}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Where("food_id = ?", f.food_id).Delete(&FoodTag{}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Delete(f).Error; err != nil {
+		return nil, err
+	}
+	invalidateFoodIndex()
+	return f, nil
+}
+
+// Merge re-points the Meals, the Recipes, the plans, the templates, the hydration entries and the shopping lists
+// from a given duplicate Food to this Food, and deletes the duplicate. The snapshots of the nutrients of the Meals are kept.
+// This Food takes over the barcode of the duplicate if it has none.
+func (f *Food) Merge(rep repository.Repository, duplicate *Food) (*Food, error) {
+	if f.food_id == duplicate.food_id {
+		return nil, ErrMergeSameFood
+	}
+	for _, table := range foodReferences {
+		if err := rep.Exec(fmt.Sprintf("update %s set food_id = ? where food_id = ?", table), f.food_id, duplicate.food_id).Error; err != nil {
+			return nil, err
+		}
+	}
+	if f.barcode == "" && duplicate.barcode != "" {
+		f.barcode = duplicate.barcode
+		duplicate.barcode = ""
+		if err := rep.Model(duplicate).Select("barcode").Updates(duplicate).Error; err != nil {
+			return nil, err
+		}
+		if err := rep.Model(f).Select("barcode").Updates(f).Error; err != nil {
+			return nil, err
+		}
+	}
+	if _, err := duplicate.Delete(rep); err != nil {
+		return nil, err
+	}
 	return f, nil
 }
 
+// IsDeleted returns true if this Food is soft-deleted.
+func (f *Food) IsDeleted() bool {
+	return f.deleted_at != nil
+}
+
+// IsGlobal returns true if this Food is in the global catalog.
+func (f *Food) IsGlobal() bool {
+	return f.user_id == nil
+}
+
+// IsVisibleTo returns true if this Food is not deleted and is in the global catalog or belongs to a given user.
+func (f *Food) IsVisibleTo(user *User) bool {
+	if f.IsDeleted() {
+		return false
+	}
+	return f.IsGlobal() || (user != nil && *f.user_id == user.user_id)
+}
+
+// IsEditableBy returns true if a given user can edit or delete this Food.
+// Only the administrator can edit the global catalog, and only the owner can edit a private Food.
+// A deleted Food cannot be edited.
+func (f *Food) IsEditableBy(user *User) bool {
+	if user == nil || f.IsDeleted() {
+		return false
+	}
+	if f.IsGlobal() {
+		return user.IsAdmin()
+	}
+	return *f.user_id == user.user_id
+}
+
+// SetTags replaces the allergen and the diet tags of this Food.
+func (f *Food) SetTags(rep repository.Repository, allergens []string, diets []string) (*Food, error) {
+	if err := rep.Where("food_id = ?", f.food_id).Delete(&FoodTag{}).Error; err != nil {
+		return nil, err
+	}
+	f.allergens, f.diets = allergens, diets
+	if err := f.createTags(rep); err != nil {
+		return nil, err
+	}
+	f.setTags(&foodTags{allergens: allergens, diets: diets})
+	return f, nil
+}
+
+// WithTags sets the allergen and the diet tags of this Food which is not persisted yet.
+// The tags of nil are regarded as unknown, so that Update keeps the current tags.
+func (f *Food) WithTags(allergens []string, diets []string) *Food {
+	f.allergens = allergens
+	f.diets = diets
+	return f
+}
+
+func (f *Food) createTags(rep repository.Repository) error {
+	for _, allergen := range f.allergens {
+		if _, err := NewFoodTag(f.food_id, TagAllergen, allergen).Create(rep); err != nil {
+			return err
+		}
+	}
+	for _, diet := range f.diets {
+		if _, err := NewFoodTag(f.food_id, TagDiet, diet).Create(rep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameAs returns true if given Food has the same name, nutrients, source, barcode and tags as this Food.
+// The tags of given Food are ignored if they are unknown.
+func (f *Food) sameAs(food *Food) bool {
+	if f.food_name != food.food_name || f.calo_amount != food.calo_amount || f.protein != food.protein ||
+		f.carbohydrate != food.carbohydrate || f.fat != food.fat || f.fiber != food.fiber ||
+		f.sugar != food.sugar || f.sodium != food.sodium || f.source != food.source || f.source_id != food.source_id || f.barcode != food.barcode {
+		return false
+	}
+	if len(f.micronutrients) != len(food.micronutrients) {
+		return false
+	}
+	for name, amount := range f.micronutrients {
+		if other, ok := food.micronutrients[name]; !ok || other != amount {
+			return false
+		}
+	}
+	if food.allergens == nil && food.diets == nil {
+		return true
+	}
+	return sameTags(f.allergens, food.allergens) && sameTags(f.diets, food.diets)
+}
+
+func sameTags(tags []string, others []string) bool {
+	if len(tags) != len(others) {
+		return false
+	}
+	set := make(map[string]bool)
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	for _, tag := range others {
+		if !set[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// grams converts given quantity of this Food in given unit to grams.
+func (f *Food) grams(quantity float64, unit string) (float64, error) {
+	grams, err := util.ToGrams(quantity, unit, util.FoodSizes{Serving: f.serving_size, Piece: f.piece_size, Density: f.density})
+	if err != nil {
+		return 0, &UnitError{Food: f.food_name, Unit: unit, Err: err}
+	}
+	return grams, nil
+}
+
+// nutrients returns the nutrients per 100 g of this Food.
+func (f *Food) nutrients() *Nutrients {
+	return NewNutrients(f.calo_amount, f.protein, f.carbohydrate, f.fat, f.fiber, f.sugar, f.sodium, f.micronutrients)
+}
+
+func (f *Food) setTags(tags *foodTags) {
+	f.allergens, f.diets = []string{}, []string{}
+	if tags != nil {
+		f.allergens = append(f.allergens, tags.allergens...)
+		f.diets = append(f.diets, tags.diets...)
+	}
+}
+
+// hasTag returns true if this Food has given tag of given kind.
+func (f *Food) hasTag(kind string, tag string) bool {
+	tags := f.diets
+	if kind == TagAllergen {
+		tags = f.allergens
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Food) setMicronutrients(micronutrients map[string]float64) {
+	if micronutrients == nil {
+		micronutrients = make(map[string]float64)
+	}
+	f.micronutrients = micronutrients
+}
+
 // ToString is return string of object
 func (f *Food) ToString() string {
 	return toString(f)
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// ImportResult defines struct of the result of importing Foods from a file.
+type ImportResult struct {
+	file     string   `json:"file"`
+	format   string   `json:"format"`
+	inserted int      `json:"inserted"`
+	updated  int      `json:"updated"`
+	skipped  int      `json:"skipped"`
+	messages []string `json:"messages"`
+}
+
+// NewImportResult is constructor
+func NewImportResult(file string, format string) *ImportResult {
+	return &ImportResult{file: file, format: format, messages: []string{}}
+}
+
+// Skip counts a row which is not imported with the reason.
+func (r *ImportResult) Skip(format string, args ...interface{}) {
+	r.skipped++
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
+
+// Summary returns the counts of this result as a string for logging.
+func (r *ImportResult) Summary() string {
+	return fmt.Sprintf("%s (%s): inserted %d, updated %d, skipped %d", r.file, r.format, r.inserted, r.updated, r.skipped)
+}
+
+// Import inserts or updates given Foods by batches of given size.
+// A Food is matched to the existing one by its barcode first, by its source and source ID next and by its name last.
+// If the matched Food has the same nutrients, it is skipped.
+// It should be called inside a transaction so that a failure does not leave a part of the Foods.
+func (r *ImportResult) Import(rep repository.Repository, foods []Food, batchSize int) error {
+	for start := 0; start < len(foods); start += batchSize {
+		end := start + batchSize
+		if end > len(foods) {
+			end = len(foods)
+		}
+		if err := r.importBatch(rep, foods[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ImportResult) importBatch(rep repository.Repository, batch []Food) error {
+	names := make([]string, 0, len(batch))
+	source_ids := make([]string, 0, len(batch))
+	barcodes := make([]string, 0, len(batch))
+	for i := range batch {
+		names = append(names, strings.ToLower(batch[i].food_name))
+		if batch[i].source_id != "" {
+			source_ids = append(source_ids, batch[i].source_id)
+		}
+		if batch[i].barcode != "" {
+			barcodes = append(barcodes, batch[i].barcode)
+		}
+	}
+
+	var existing []Food
+	if err := rep.Where("user_id is null and deleted_at is null and (lower(food_name) in ? or source_id in ? or barcode in ?)", names, source_ids, barcodes).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+	byBarcode := make(map[string]*Food)
+	bySource := make(map[string]*Food)
+	byName := make(map[string]*Food)
+	for i := range existing {
+		if existing[i].barcode != "" {
+			byBarcode[existing[i].barcode] = &existing[i]
+		}
+		if existing[i].source_id != "" {
+			bySource[existing[i].source+"/"+existing[i].source_id] = &existing[i]
+		}
+		byName[strings.ToLower(existing[i].food_name)] = &existing[i]
+	}
+
+	if err := loadFoodDetails(rep, existing); err != nil {
+		return err
+	}
+
+	for i := range batch {
+		food := &batch[i]
+		matched, ok := byBarcode[food.barcode]
+		if !ok || food.barcode == "" {
+			matched, ok = bySource[food.source+"/"+food.source_id]
+		}
+		if !ok || food.source_id == "" {
+			matched, ok = byName[strings.ToLower(food.food_name)]
+		}
+
+		switch {
+		case !ok:
+			if _, err := food.Create(rep); err != nil {
+				return err
+			}
+			r.inserted++
+			matched = food
+		case matched.sameAs(food):
+			r.skipped++
+		default:
+			if _, err := matched.Update(rep, food); err != nil {
+				return err
+			}
+			r.updated++
+		}
+
+		if matched.barcode != "" {
+			byBarcode[matched.barcode] = matched
+		}
+		if matched.source_id != "" {
+			bySource[matched.source+"/"+matched.source_id] = matched
+		}
+		byName[strings.ToLower(matched.food_name)] = matched
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// FoodNutrient defines struct of the amount of a micronutrient per 100 g of a Food.
+type FoodNutrient struct {
+	food_nutrient_id uint    `gorm:"primary_key" json:"id"`
+	food_id          uint    `json:"food_id"`
+	nutrient_name    string  `json:"nutrient_name"`
+	amount           float64 `json:"amount"`
+}
+
+// TableName returns the table name of FoodNutrient struct and it is used by gorm.
+func (FoodNutrient) TableName() string {
+	return "food_nutrients"
+}
+
+// NewFoodNutrient is constructor
+func NewFoodNutrient(food_id uint, nutrient_name string, amount float64) *FoodNutrient {
+	return &FoodNutrient{food_id: food_id, nutrient_name: nutrient_name, amount: amount}
+}
+
+// FindByFoodIDs returns the micronutrients of given Foods grouped by the Food's ID.
+func (n *FoodNutrient) FindByFoodIDs(rep repository.Repository, food_ids []uint) (map[uint]map[string]float64, error) {
+	result := make(map[uint]map[string]float64)
+	if len(food_ids) == 0 {
+		return result, nil
+	}
+
+	var nutrients []FoodNutrient
+	if err := rep.Where("food_id in ?", food_ids).Find(&nutrients).Error; err != nil {
+		return nil, err
+	}
+	for i := range nutrients {
+		if _, ok := result[nutrients[i].food_id]; !ok {
+			result[nutrients[i].food_id] = make(map[string]float64)
+		}
+		result[nutrients[i].food_id][nutrients[i].nutrient_name] = nutrients[i].amount
+	}
+	return result, nil
+}
+
+// Create persists this FoodNutrient data.
+func (n *FoodNutrient) Create(rep repository.Repository) (*FoodNutrient, error) {
+	if err := rep.Select("food_id", "nutrient_name", "amount").Create(n).Error; err != nil {
+		return nil, err
+	}
+	return n, nil
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	// minTextSearchLength is the length of the shortest query which is searched by trigrams.
+	// The shorter queries are searched by prefix for autocomplete.
+	minTextSearchLength = 3
+	searchFoodsByPrefix = "select food_id as id, -length(food_name) as score from foods where lower(food_name) like ?"
+	orderSearchResult   = "select s.id as id from (%s) s where %s order by s.score desc, s.id"
+	filterFoods         = "select f.food_id as id from foods f where f.food_id in ? and "
+)
+
+// foodIndex is the in-memory trigram index of the food names used when the database has no index for the text search.
+// It is built on the first search and dropped when a Food is created or updated.
+var foodIndex struct {
+	sync.Mutex
+	index *util.TrigramIndex
+}
+
+// RecordSearchResult defines struct represents the ID of a matched row.
+type RecordSearchResult struct {
+	id uint
+}
+
+// Search returns the page object of Foods similar to given query in order of relevance.
+// It tolerates typos by the trigram index of the database, or by the in-memory index if the database has none.
+// The query shorter than 3 characters matches the Foods which start with it.
+// The Foods which do not meet given filter on the owner, the allergens and the diets are excluded.
+func (f *Food) Search(rep repository.Repository, query string, filter *FoodFilter, page string, size string) (*Page, error) {
+	query = strings.TrimSpace(query)
+
+	var sqlquery string
+	var args []interface{}
+	var err error
+	if utf8.RuneCountInString(query) < minTextSearchLength {
+		sqlquery, args = searchFoodsByPrefix, []interface{}{strings.ToLower(query) + "%"}
+	} else if sqlquery, args, err = rep.MatchText("foods", "food_id", "food_name", query); errors.Is(err, repository.ErrTextSearchUnavailable) {
+		return f.searchIndex(rep, query, filter, page, size)
+	} else if err != nil {
+		return nil, err
+	}
+
+	condition, conditionArgs := filter.condition("s.id")
+	args = append(args, conditionArgs...)
+	sqlquery = fmt.Sprintf(orderSearchResult, sqlquery, condition)
+
+	total, err := countRows(rep, sqlquery, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	var rec RecordSearchResult
+	var rows *sql.Rows
+	if rows, err = createRaw(rep, sqlquery, page, size, args).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		ids = append(ids, rec.id)
+	}
+
+	foods, err := f.FindByIDs(rep, ids)
+	if err != nil {
+		return nil, err
+	}
+	return createPage(&foods, total, page, size), nil
+}
+
+// FindByIDs returns the Foods matched given IDs in the same order.
+func (f *Food) FindByIDs(rep repository.Repository, ids []uint) ([]Food, error) {
+	foods := []Food{}
+	if len(ids) == 0 {
+		return foods, nil
+	}
+
+	var found []Food
+	if err := rep.Where("food_id in ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if err := loadFoodDetails(rep, found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*Food)
+	for i := range found {
+		byID[found[i].food_id] = &found[i]
+	}
+	for _, id := range ids {
+		if food, ok := byID[id]; ok {
+			foods = append(foods, *food)
+		}
+	}
+	return foods, nil
+}
+
+// searchIndex searches the Foods by the in-memory trigram index.
+func (f *Food) searchIndex(rep repository.Repository, query string, filter *FoodFilter, page string, size string) (*Page, error) {
+	foodIndex.Lock()
+	if foodIndex.index == nil {
+		var foods []Food
+		if err := rep.Select("food_id", "food_name").Find(&foods).Error; err != nil {
+			foodIndex.Unlock()
+			return nil, err
+		}
+		foodIndex.index = util.NewTrigramIndex()
+		for i := range foods {
+			foodIndex.index.Add(foods[i].food_id, foods[i].food_name)
+		}
+	}
+	matches := foodIndex.index.Search(query)
+	foodIndex.Unlock()
+
+	ids := make([]uint, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].ID
+	}
+	ids, err := filter.apply(rep, ids)
+	if err != nil {
+		return nil, err
+	}
+	total := len(ids)
+	if util.IsNumeric(page) && util.IsNumeric(size) {
+		start := util.ConvertToInt(page) * util.ConvertToInt(size)
+		if start > len(ids) {
+			start = len(ids)
+		}
+		end := start + util.ConvertToInt(size)
+		if end > len(ids) {
+			end = len(ids)
+		}
+		ids = ids[start:end]
+	}
+
+	foods, err := f.FindByIDs(rep, ids)
+	if err != nil {
+		return nil, err
+	}
+	return createPage(&foods, total, page, size), nil
+}
+
+// apply returns the IDs of the Foods which meet this FoodFilter in the same order.
+func (t *FoodFilter) apply(rep repository.Repository, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	condition, args := t.condition("f.food_id")
+	var rec RecordSearchResult
+	var rows *sql.Rows
+	var err error
+	if rows, err = rep.Raw(filterFoods+condition, append([]interface{}{ids}, args...)...).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accepted := make(map[uint]bool)
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		accepted[rec.id] = true
+	}
+	result := []uint{}
+	for _, id := range ids {
+		if accepted[id] {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// invalidateFoodIndex drops the in-memory trigram index so that it is rebuilt with the changed Foods.
+func invalidateFoodIndex() {
+	foodIndex.Lock()
+	foodIndex.index = nil
+	foodIndex.Unlock()
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+const (
+	// TagAllergen represents the tag of an allergen which a Food contains.
+	TagAllergen = "allergen"
+	// TagDiet represents the tag of a diet which a Food is suitable for.
+	TagDiet = "diet"
+)
+
+// Allergens are the allergen tags which a Food can have.
+var Allergens = []string{"gluten", "nuts", "peanuts", "dairy", "egg", "soy", "fish", "shellfish", "sesame"}
+
+// Diets are the diet tags which a Food can have.
+var Diets = []string{"vegan", "vegetarian", "halal", "kosher", "keto"}
+
+// FoodTag defines struct of an allergen or a diet tag of a Food.
+type FoodTag struct {
+	food_tag_id uint   `gorm:"primary_key" json:"id"`
+	food_id     uint   `json:"food_id"`
+	kind        string `json:"kind"`
+	tag         string `json:"tag"`
+}
+
+// foodTags defines struct of the allergen and the diet tags of a Food.
+type foodTags struct {
+	allergens []string
+	diets     []string
+}
+
+// FoodFilter defines struct of the conditions on the owner and the tags of the Foods to search.
+// It excludes the private Foods of the other users and the Foods which contain any of the allergens,
+// and requires the Foods suitable for all of the diets.
+type FoodFilter struct {
+	user      *User
+	allergens []string
+	diets     []string
+}
+
+const findFoodIDsByTags = "select food_id from food_tags where kind = ? and tag in ?"
+
+// TableName returns the table name of FoodTag struct and it is used by gorm.
+func (FoodTag) TableName() string {
+	return "food_tags"
+}
+
+// NewFoodTag is constructor
+func NewFoodTag(food_id uint, kind string, tag string) *FoodTag {
+	return &FoodTag{food_id: food_id, kind: kind, tag: tag}
+}
+
+// FindByFoodIDs returns the tags of given Foods grouped by the Food's ID.
+func (t *FoodTag) FindByFoodIDs(rep repository.Repository, food_ids []uint) (map[uint]*foodTags, error) {
+	result := make(map[uint]*foodTags)
+	if len(food_ids) == 0 {
+		return result, nil
+	}
+
+	var tags []FoodTag
+	if err := rep.Where("food_id in ?", food_ids).Order("tag").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for i := range tags {
+		if _, ok := result[tags[i].food_id]; !ok {
+			result[tags[i].food_id] = &foodTags{}
+		}
+		switch tags[i].kind {
+		case TagAllergen:
+			result[tags[i].food_id].allergens = append(result[tags[i].food_id].allergens, tags[i].tag)
+		case TagDiet:
+			result[tags[i].food_id].diets = append(result[tags[i].food_id].diets, tags[i].tag)
+		}
+	}
+	return result, nil
+}
+
+// Create persists this FoodTag data.
+func (t *FoodTag) Create(rep repository.Repository) (*FoodTag, error) {
+	if err := rep.Select("food_id", "kind", "tag").Create(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewFoodFilter is constructor. The Foods visible to given user are searched, or only the global catalog if it is nil.
+func NewFoodFilter(user *User, allergens []string, diets []string) *FoodFilter {
+	return &FoodFilter{user: user, allergens: allergens, diets: diets}
+}
+
+// condition returns the SQL condition on given column of the Food's ID and its arguments.
+func (t *FoodFilter) condition(column string) (string, []interface{}) {
+	conditions := []string{column + " in (select food_id from foods where " + findVisibleFoods + ")"}
+	args := []interface{}{uint(0)}
+	if t.user != nil {
+		args[0] = t.user.user_id
+	}
+	if len(t.allergens) > 0 {
+		conditions = append(conditions, column+" not in ("+findFoodIDsByTags+")")
+		args = append(args, TagAllergen, t.allergens)
+	}
+	for _, diet := range t.diets {
+		conditions = append(conditions, column+" in ("+findFoodIDsByTags+")")
+		args = append(args, TagDiet, []string{diet})
+	}
+	return strings.Join(conditions, " and "), args
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFoodVisibilityAndEditability(t *testing.T) {
+	admin := &User{user_id: 1, authority_id: AuthorityAdmin}
+	owner := &User{user_id: 2, authority_id: AuthorityUser}
+	other := &User{user_id: 3, authority_id: AuthorityUser}
+
+	deletedAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	global := NewFood("Rice", &Nutrients{})
+	private := NewUserFood(owner, "Grandma's Soup", &Nutrients{})
+	deleted := NewFood("Discontinued Bar", &Nutrients{})
+	deleted.deleted_at = &deletedAt
+
+	// The expectations are for the users in order: nil, admin, owner, other.
+	users := []*User{nil, admin, owner, other}
+	cases := []struct {
+		name     string
+		food     *Food
+		visible  [4]bool
+		editable [4]bool
+	}{
+		{"global food", global, [4]bool{true, true, true, true}, [4]bool{false, true, false, false}},
+		{"private food", private, [4]bool{false, false, true, false}, [4]bool{false, false, true, false}},
+		{"deleted food", deleted, [4]bool{false, false, false, false}, [4]bool{false, false, false, false}},
+	}
+	for _, tc := range cases {
+		for i, user := range users {
+			if got := tc.food.IsVisibleTo(user); got != tc.visible[i] {
+				t.Errorf("%s: IsVisibleTo(%v) = %v, want %v", tc.name, user, got, tc.visible[i])
+			}
+			if got := tc.food.IsEditableBy(user); got != tc.editable[i] {
+				t.Errorf("%s: IsEditableBy(%v) = %v, want %v", tc.name, user, got, tc.editable[i])
+			}
+		}
+	}
+}
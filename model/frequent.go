@@ -0,0 +1,107 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// FrequentFood defines struct of a Food which a user has logged frequently
+// with the portion logged last time, so that the user can log it again as it is.
+type FrequentFood struct {
+	food           Food      `json:"food"`
+	times          int       `json:"times"`
+	last_logged_at time.Time `json:"last_logged_at"`
+	quantity       float64   `json:"quantity"`
+	unit           string    `json:"unit"`
+}
+
+// RecordFrequentFood defines struct represents the record of the database.
+type RecordFrequentFood struct {
+	food_id uint
+	times   int
+}
+
+// RecordLastPortion defines struct represents the record of the database.
+type RecordLastPortion struct {
+	food_id  uint
+	meal_at  time.Time
+	quantity float64
+	unit     string
+}
+
+const (
+	selectFrequentFood = "select i.food_id as food_id, count(*) as times " +
+		"from meal_items i inner join meals m on m.meal_id = i.meal_id " +
+		"where m.user_id = ? and m.meal_at >= ? group by i.food_id order by times desc, max(m.meal_at) desc limit ?"
+	selectLastPortion = "select i.food_id as food_id, m.meal_at as meal_at, i.quantity as quantity, i.unit as unit " +
+		"from meal_items i inner join meals m on m.meal_id = i.meal_id " +
+		"where m.user_id = ? and i.food_id in ? order by m.meal_at desc, i.meal_item_id desc"
+)
+
+// FindByUser returns the Foods which a given user has logged most frequently since a given time, up to a given number.
+func (f *FrequentFood) FindByUser(rep repository.Repository, user *User, since time.Time, limit int) (*[]FrequentFood, error) {
+	var rec RecordFrequentFood
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(selectFrequentFood, user.user_id, since.UTC(), limit).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	times := make(map[uint]int)
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		ids = append(ids, rec.food_id)
+		times[rec.food_id] = rec.times
+	}
+
+	result := []FrequentFood{}
+	if len(ids) == 0 {
+		return &result, nil
+	}
+
+	portions, err := findLastPortions(rep, user, ids)
+	if err != nil {
+		return nil, err
+	}
+	food := Food{}
+	foods, err := food.FindByIDs(rep, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range foods {
+		portion := portions[foods[i].food_id]
+		result = append(result, FrequentFood{food: foods[i], times: times[foods[i].food_id],
+			last_logged_at: portion.meal_at, quantity: portion.quantity, unit: portion.unit})
+	}
+	return &result, nil
+}
+
+// findLastPortions returns the portions of given Foods which a given user has logged last time.
+func findLastPortions(rep repository.Repository, user *User, food_ids []uint) (map[uint]RecordLastPortion, error) {
+	var rec RecordLastPortion
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(selectLastPortion, user.user_id, food_ids).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uint]RecordLastPortion)
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		if _, ok := result[rec.food_id]; !ok {
+			result[rec.food_id] = rec
+		}
+	}
+	return result, nil
+}
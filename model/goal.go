@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// Goal defines struct of the daily calorie and macronutrient goal of a user.
+// Goals are never overwritten, a new Goal is added from its effective date so that
+// past days are evaluated against the Goal which applied then.
+type Goal struct {
+	goal_id            uint      `gorm:"primary_key" json:"id"`
+	user_id            uint      `json:"user_id"`
+	calories           float64   `json:"calories"`
+	protein_ratio      float64   `json:"protein_ratio"`
+	carbohydrate_ratio float64   `json:"carbohydrate_ratio"`
+	fat_ratio          float64   `json:"fat_ratio"`
+	effective_from     time.Time `json:"effective_from"`
+	created_at         time.Time `json:"created_at"`
+}
+
+const (
+	// kcal per gram of macronutrients
+	caloriesPerProtein      = 4
+	caloriesPerCarbohydrate = 4
+	caloriesPerFat          = 9
+)
+
+// TableName returns the table name of Goal struct and it is used by gorm.
+func (Goal) TableName() string {
+	return "goals"
+}
+
+// NewGoal is constructor
+func NewGoal(user *User, calories float64, protein_ratio float64, carbohydrate_ratio float64, fat_ratio float64, effective_from time.Time) *Goal {
+	return &Goal{user_id: user.user_id, calories: calories, protein_ratio: protein_ratio,
+		carbohydrate_ratio: carbohydrate_ratio, fat_ratio: fat_ratio, effective_from: effective_from}
+}
+
+// FindByUser returns the history of the Goals of a given user, the newest first.
+func (g *Goal) FindByUser(rep repository.Repository, user *User) (*[]Goal, error) {
+	var goals []Goal
+	if err := rep.Where("user_id = ?", user.user_id).
+		Order("effective_from desc, goal_id desc").Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	return &goals, nil
+}
+
+// FindActiveByUserAndDate returns the Goal of a given user which applies to a given day.
+func (g *Goal) FindActiveByUserAndDate(rep repository.Repository, user *User, date time.Time) optional.Option[*Goal] {
+	var goal Goal
+	to := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).AddDate(0, 0, 1)
+	if err := rep.Where("user_id = ? and effective_from < ?", user.user_id, to).
+		Order("effective_from desc, goal_id desc").First(&goal).Error; err != nil {
+		return optional.None[*Goal]()
+	}
+	return optional.Some(&goal)
+}
+
+// Create persists this Goal data.
+func (g *Goal) Create(rep repository.Repository) (*Goal, error) {
+	g.created_at = time.Now()
+	if err := rep.Select("user_id", "calories", "protein_ratio", "carbohydrate_ratio", "fat_ratio",
+		"effective_from", "created_at").Create(g).Error; err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// targets returns the target nutrients of this Goal, which macronutrients are converted into grams.
+func (g *Goal) targets() *Nutrients {
+	return NewNutrients(g.calories,
+		g.calories*g.protein_ratio/100/caloriesPerProtein,
+		g.calories*g.carbohydrate_ratio/100/caloriesPerCarbohydrate,
+		g.calories*g.fat_ratio/100/caloriesPerFat,
+		0, 0, 0, nil)
+}
+
+// ToString is return string of object
+func (g *Goal) ToString() string {
+	return toString(g)
+}
@@ -0,0 +1,224 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// waterPerWeight is the daily water target in ml per kg of the body weight.
+	waterPerWeight = 35
+	// defaultWaterTarget is the daily water target in ml when the body weight is not registered.
+	defaultWaterTarget = 2000
+)
+
+// HydrationEntry defines struct of a beverage which a user has drunk.
+// If the beverage is also a Food such as juice or soda, food_id refers to it
+// and the calories are computed regarding 1 ml as 1 g.
+// The nutrients per 100 ml of the Food are snapshotted when the beverage is logged at calculated_at,
+// so that editing or merging the Food does not change the past days in the same way as the MealItems.
+type HydrationEntry struct {
+	hydration_id  uint       `gorm:"primary_key" json:"id"`
+	user_id       uint       `json:"user_id"`
+	volume        float64    `json:"volume"`
+	beverage_type string     `json:"beverage_type"`
+	food_id       *uint      `json:"food_id"`
+	drank_at      time.Time  `json:"drank_at"`
+	calo_amount   float64    `json:"-"`
+	protein       float64    `json:"-"`
+	carbohydrate  float64    `json:"-"`
+	fat           float64    `json:"-"`
+	fiber         float64    `json:"-"`
+	sugar         float64    `json:"-"`
+	sodium        float64    `json:"-"`
+	calculated_at *time.Time `json:"calculated_at"`
+	nutrients     Nutrients  `gorm:"-" json:"nutrients"`
+}
+
+// RecordHydrationEntry defines struct represents the record of the database.
+type RecordHydrationEntry struct {
+	hydration_id  uint
+	user_id       uint
+	volume        float64
+	beverage_type string
+	food_id       *uint
+	drank_at      time.Time
+	calculated_at *time.Time
+	calo_amount   float64
+	protein       float64
+	carbohydrate  float64
+	fat           float64
+	fiber         float64
+	sugar         float64
+	sodium        float64
+}
+
+// Hydration defines struct of the beverages which a user has drunk in a day.
+type Hydration struct {
+	volume    float64   `json:"volume"`
+	target    float64   `json:"target"`
+	remaining float64   `json:"remaining"`
+	nutrients Nutrients `json:"nutrients"`
+}
+
+const (
+	selectHydration = "select h.hydration_id as hydration_id, h.user_id as user_id, h.volume as volume, " +
+		"h.beverage_type as beverage_type, h.food_id as food_id, h.drank_at as drank_at, h.calculated_at as calculated_at, " +
+		"h.calo_amount as calo_amount, h.protein as protein, h.carbohydrate as carbohydrate, h.fat as fat, " +
+		"h.fiber as fiber, h.sugar as sugar, h.sodium as sodium from hydration_entries h"
+	findHydrationByUser        = " where h.user_id = ? order by h.drank_at desc"
+	findHydrationByUserAndDate = " where h.user_id = ? and h.drank_at >= ? and h.drank_at < ? order by h.drank_at"
+	// snapshotHydrationColumn is the SQL expression of a column of the food of an entry in the update of the entries.
+	// The entries of the plain beverages have no food and their nutrients are 0.
+	snapshotHydrationColumn = "coalesce((select %s from foods f where f.food_id = hydration_entries.food_id), 0)"
+)
+
+// TableName returns the table name of HydrationEntry struct and it is used by gorm.
+func (HydrationEntry) TableName() string {
+	return "hydration_entries"
+}
+
+// NewHydrationEntry is constructor
+func NewHydrationEntry(user *User, volume float64, beverage_type string, food_id *uint, drank_at time.Time) *HydrationEntry {
+	return &HydrationEntry{user_id: user.user_id, volume: volume, beverage_type: beverage_type, food_id: food_id, drank_at: drank_at}
+}
+
+// FindByID returns a HydrationEntry of a given user full matched given ID.
+func (h *HydrationEntry) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*HydrationEntry] {
+	var entry HydrationEntry
+	if err := rep.Where("hydration_id = ? and user_id = ?", id, user.user_id).First(&entry).Error; err != nil {
+		return optional.None[*HydrationEntry]()
+	}
+	return optional.Some(&entry)
+}
+
+// FindByUserAndPage returns the page object of the HydrationEntries of a given user, the newest first.
+func (h *HydrationEntry) FindByUserAndPage(rep repository.Repository, user *User, page string, size string) (*Page, error) {
+	args := []interface{}{user.user_id}
+	total, err := countRows(rep, selectHydration+findHydrationByUser, args)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := findHydrationRows(rep, selectHydration+findHydrationByUser, page, size, args)
+	if err != nil {
+		return nil, err
+	}
+	return createPage(&entries, total, page, size), nil
+}
+
+// Create persists this HydrationEntry data. drank_at is stored in UTC in the same way as meal_at of the Meals.
+func (h *HydrationEntry) Create(rep repository.Repository) (*HydrationEntry, error) {
+	h.drank_at = h.drank_at.UTC()
+	per100ml := NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)
+	if h.food_id != nil {
+		food := Food{}
+		f, err := food.FindVisibleByID(rep, &User{user_id: h.user_id}, *h.food_id).Take()
+		if err != nil {
+			return nil, err
+		}
+		per100ml = f.nutrients()
+	}
+	h.snapshot(per100ml)
+	if err := rep.Select("user_id", "volume", "beverage_type", "food_id", "drank_at", "calo_amount", "protein",
+		"carbohydrate", "fat", "fiber", "sugar", "sodium", "calculated_at").Create(h).Error; err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Snapshot fills the snapshots of the entries which were logged before the nutrients were snapshotted
+// with the current nutrients of their foods.
+func (h *HydrationEntry) Snapshot(rep repository.Repository) error {
+	columns := map[string]interface{}{"calculated_at": time.Now()}
+	for _, column := range []string{"calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium"} {
+		columns[column] = gorm.Expr(fmt.Sprintf(snapshotHydrationColumn, "f."+column))
+	}
+	return rep.Model(&HydrationEntry{}).Where("calculated_at is null").Updates(columns).Error
+}
+
+// snapshot copies given nutrients per 100 ml of the Food of this HydrationEntry and computes the nutrients of its volume.
+func (h *HydrationEntry) snapshot(per100ml *Nutrients) {
+	now := time.Now()
+	h.calculated_at = &now
+	h.calo_amount, h.protein, h.carbohydrate, h.fat = per100ml.calories, per100ml.protein, per100ml.carbohydrate, per100ml.fat
+	h.fiber, h.sugar, h.sodium = per100ml.fiber, per100ml.sugar, per100ml.sodium
+	h.calculate()
+}
+
+// calculate computes the nutrients of the volume of this HydrationEntry from the snapshot, regarding 1 ml as 1 g.
+func (h *HydrationEntry) calculate() {
+	h.nutrients = *portion(NewNutrients(h.calo_amount, h.protein, h.carbohydrate, h.fat, h.fiber, h.sugar, h.sodium, nil), h.volume)
+}
+
+// Delete deletes this HydrationEntry data.
+func (h *HydrationEntry) Delete(rep repository.Repository) (*HydrationEntry, error) {
+	if err := rep.Delete(h).Error; err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// FindByUserAndDate returns the total volume, the target and the calories of the beverages
+// which a given user has drunk in a given day. The target is 35 ml per kg of the latest weight.
+func (d *Hydration) FindByUserAndDate(rep repository.Repository, user *User, date time.Time) (*Hydration, error) {
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	args := []interface{}{user.user_id, from.UTC(), from.AddDate(0, 0, 1).UTC()}
+
+	entries, err := findHydrationRows(rep, selectHydration+findHydrationByUserAndDate, "", "", args)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Hydration{target: defaultWaterTarget, nutrients: *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)}
+	entry := WeightEntry{}
+	if w, err := entry.FindLatestByUserAndDate(rep, user, from).Take(); err == nil {
+		result.target = w.weight * waterPerWeight
+	}
+	for i := range entries {
+		result.volume += entries[i].volume
+		result.nutrients.add(&entries[i].nutrients)
+	}
+	result.remaining = result.target - result.volume
+	return result, nil
+}
+
+// NormalizeDrankTimes converts drank_at of the HydrationEntries which were stored with the offset of a time zone to UTC.
+func (h *HydrationEntry) NormalizeDrankTimes(rep repository.Repository) error {
+	return normalizeTimes(rep, "hydration_entries", "hydration_id", "drank_at")
+}
+
+func findHydrationRows(rep repository.Repository, sqlquery string, page string, size string, args []interface{}) ([]HydrationEntry, error) {
+	entries := []HydrationEntry{}
+
+	var rec RecordHydrationEntry
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = createRaw(rep, sqlquery, page, size, args).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		entry := HydrationEntry{hydration_id: rec.hydration_id, user_id: rec.user_id, volume: rec.volume,
+			beverage_type: rec.beverage_type, food_id: rec.food_id, drank_at: rec.drank_at, calculated_at: rec.calculated_at,
+			calo_amount: rec.calo_amount, protein: rec.protein, carbohydrate: rec.carbohydrate, fat: rec.fat,
+			fiber: rec.fiber, sugar: rec.sugar, sodium: rec.sodium}
+		entry.calculate()
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ToString is return string of object
+func (h *HydrationEntry) ToString() string {
+	return toString(h)
+}
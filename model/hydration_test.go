@@ -0,0 +1,47 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHydrationEntrySnapshotIsKeptAfterFoodEdit(t *testing.T) {
+	juice := NewFood("Orange Juice", NewNutrients(45, 0.7, 10.4, 0.2, 0.2, 8.4, 1, nil))
+	entry := &HydrationEntry{volume: 250}
+	entry.snapshot(juice.nutrients())
+
+	// The Food is edited or merged into another one after the beverage was logged.
+	juice.calo_amount, juice.sugar = 120, 25
+
+	if entry.calculated_at == nil {
+		t.Fatal("calculated_at is nil, want the time of the snapshot")
+	}
+	if entry.calo_amount != 45 || entry.sugar != 8.4 {
+		t.Errorf("snapshot = %v kcal and %v g sugar per 100 ml, want 45 and 8.4", entry.calo_amount, entry.sugar)
+	}
+
+	// The nutrients are computed from the snapshot again when the entry is read back.
+	read := HydrationEntry{volume: entry.volume, calo_amount: entry.calo_amount, protein: entry.protein,
+		carbohydrate: entry.carbohydrate, fat: entry.fat, fiber: entry.fiber, sugar: entry.sugar, sodium: entry.sodium}
+	read.calculate()
+	for _, got := range []*HydrationEntry{entry, &read} {
+		if math.Abs(got.nutrients.calories-112.5) > 1e-9 {
+			t.Errorf("calories = %v, want 112.5 for 250 ml", got.nutrients.calories)
+		}
+		if math.Abs(got.nutrients.sugar-21) > 1e-9 {
+			t.Errorf("sugar = %v, want 21 for 250 ml", got.nutrients.sugar)
+		}
+	}
+}
+
+func TestHydrationEntryWithoutFoodHasNoNutrients(t *testing.T) {
+	water := &HydrationEntry{volume: 500, beverage_type: "water"}
+	water.snapshot(NewNutrients(0, 0, 0, 0, 0, 0, 0, nil))
+
+	if water.calculated_at == nil {
+		t.Error("calculated_at is nil, want the plain beverage to be marked as snapshotted")
+	}
+	if water.nutrients.calories != 0 || water.nutrients.sodium != 0 {
+		t.Errorf("nutrients = %+v, want zero", water.nutrients)
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"math"
 	"time"
 
@@ -13,12 +14,27 @@ import (
 )
 
 // Meal defines struct of Meal data.
+// The meal_type is inferred from the time of day of meal_at in the time zone of the user if it is not supplied.
+// The meal_at is stored in UTC, so that the times compare in order even where the database compares them as strings.
+// The Meals of a user are indexed by meal_at, which the cursor pages seek by.
 type Meal struct {
-	meal_id   uint      `gorm:"primary_key" json:"id"`
-	meal_name string    `json:"meal_name"`
-	user_id   uint      `json:"user_id"`
-	food_id   uint      `json:"food_id"`
-	meal_at   time.Time `json:"meal_at"`
+	meal_id   uint         `gorm:"primary_key" json:"id"`
+	meal_name string       `json:"meal_name"`
+	user_id   uint         `gorm:"index:idx_meals_user_meal_at,priority:1" json:"user_id"`
+	meal_at   time.Time    `gorm:"index:idx_meals_user_meal_at,priority:2" json:"meal_at"`
+	meal_type string       `json:"meal_type"`
+	items     []MealItem   `gorm:"-" json:"items"`
+	calories  float64      `gorm:"-" json:"calories"`
+	nutrients Nutrients    `gorm:"-" json:"nutrients"`
+	recipes   []MealRecipe `gorm:"-" json:"-"`
+	warnings  []string     `gorm:"-" json:"warnings,omitempty"`
+}
+
+// MealRecipe defines struct of a Recipe and its servings which a Meal is made from.
+// The Recipe is expanded into the items of the Meal when the Meal is created.
+type MealRecipe struct {
+	recipe_id uint
+	servings  float64
 }
 
 // RecordMeal defines struct represents the record of the database.
@@ -26,35 +42,163 @@ type RecordMeal struct {
 	meal_id   uint
 	meal_name string
 	user_id   uint
-	food_id   uint
 	meal_at   time.Time
+	meal_type string
+}
+
+// RecordTime defines struct represents the record of the database.
+type RecordTime struct {
+	id uint
+	at time.Time
+}
+
+// RecordUnclassifiedMeal defines struct represents the record of the database.
+type RecordUnclassifiedMeal struct {
+	meal_id  uint
+	meal_at  time.Time
+	timezone string
 }
 
 const (
-	selectMeal = "select m.meal_id as meal_id, m.meal_name as meal_name, m.meal_at as meal_at, " +
-		"f.food_id as food_id, f.food_name as food_name" +
-		"from meals m inner join foods f on f.food_id = m.food_id"
-	findByID   = " where m.meal_id = ?"
-	findByName = " where meal_name like ? "
+	// MealTypeBreakfast represents the meal from 5:00 to 10:59.
+	MealTypeBreakfast = "breakfast"
+	// MealTypeLunch represents the meal from 11:00 to 14:59.
+	MealTypeLunch = "lunch"
+	// MealTypeDinner represents the meal from 17:00 to 21:59.
+	MealTypeDinner = "dinner"
+	// MealTypeSnack represents the meal at the other times.
+	MealTypeSnack = "snack"
 )
 
+const (
+	selectMeal = "select m.meal_id as meal_id, m.meal_name as meal_name, m.meal_at as meal_at, m.user_id as user_id, " +
+		"m.meal_type as meal_type from meals m"
+	selectUnclassifiedMeal = "select m.meal_id as meal_id, m.meal_at as meal_at, u.timezone as timezone " +
+		"from meals m inner join users u on u.user_id = m.user_id where m.meal_type is null or m.meal_type = ''"
+	findByID          = " where m.meal_id = ?"
+	findByUserAndDate = " where m.user_id = ? and m.meal_at >= ? and m.meal_at < ? order by m.meal_at"
+	countRowsSQL      = "select count(*) from (%s) c"
+	// selectTime and updateTime are the SQL which read and write a column of the time of a row of a table by its key.
+	selectTime = "select %s as id, %s as at from %s"
+	updateTime = "update %s set %s = ? where %s = ?"
+)
+
+// ErrMealNotOwned is returned when a Meal is changed by the user who does not own it.
+var ErrMealNotOwned = errors.New("the meal is not owned by the user")
+
 // TableName returns the table name of Meal struct and it is used by gorm.
 func (Meal) TableName() string {
-	return "Meal"
+	return "meals"
+}
+
+// NewMeal is constructor of the Meal of a given user.
+func NewMeal(meal_name string, user *User, meal_at time.Time, items []MealItem, recipes []MealRecipe) *Meal {
+	return &Meal{meal_name: meal_name, user_id: user.user_id, meal_at: meal_at, items: items, recipes: recipes}
+}
+
+// SetMealType sets the meal type of this Meal. It is inferred when the Meal is created if it is empty.
+func (m *Meal) SetMealType(meal_type string) *Meal {
+	m.meal_type = meal_type
+	return m
+}
+
+// mealTypeAt returns the meal type of a meal taken at given time in its time zone.
+func mealTypeAt(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 11:
+		return MealTypeBreakfast
+	case hour >= 11 && hour < 15:
+		return MealTypeLunch
+	case hour >= 17 && hour < 22:
+		return MealTypeDinner
+	}
+	return MealTypeSnack
+}
+
+// ClassifyMealTypes infers the meal types of the Meals which have no meal type in the time zones of their users.
+func (m *Meal) ClassifyMealTypes(rep repository.Repository) error {
+	var rec RecordUnclassifiedMeal
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(selectUnclassifiedMeal).Rows(); err != nil {
+		return err
+	}
+	var meals []Meal
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			rows.Close()
+			return err
+		}
+		meals = append(meals, Meal{meal_id: rec.meal_id, meal_type: mealTypeAt(rec.meal_at.In(util.LoadLocation(rec.timezone)))})
+	}
+	rows.Close()
+
+	for i := range meals {
+		if err := rep.Model(&meals[i]).Select("meal_type").Updates(&meals[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NormalizeMealTimes converts meal_at of the Meals which were stored with the offset of a time zone to UTC.
+func (m *Meal) NormalizeMealTimes(rep repository.Repository) error {
+	return normalizeTimes(rep, "meals", "meal_id", "meal_at")
+}
+
+// normalizeTimes converts given column of the time of the rows of given table which were stored
+// with the offset of a time zone to UTC. The rows are identified by given key.
+func normalizeTimes(rep repository.Repository, table string, key string, column string) error {
+	var rec RecordTime
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(fmt.Sprintf(selectTime, key, column, table)).Rows(); err != nil {
+		return err
+	}
+	var records []RecordTime
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			rows.Close()
+			return err
+		}
+		if _, offset := rec.at.Zone(); offset != 0 {
+			records = append(records, RecordTime{id: rec.id, at: rec.at.UTC()})
+		}
+	}
+	rows.Close()
+
+	for i := range records {
+		if err := rep.Exec(fmt.Sprintf(updateTime, table, column, key), records[i].at, records[i].id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// NewMeal is constructor
-func NewMeal(meal_name string, user_id uint, food_id uint, meal_at time.Time) *Meal {
-	return &Meal{meal_name: meal_name, user_id: user_id, food_id: food_id, meal_at: meal_at}
+// NewMealRecipe is constructor
+func NewMealRecipe(recipe_id uint, servings float64) *MealRecipe {
+	return &MealRecipe{recipe_id: recipe_id, servings: servings}
 }
 
 // FindByID returns a Meal full matched given Meal's ID.
 func (m *Meal) FindByID(rep repository.Repository, id uint) optional.Option[*Meal] {
-	var rec RecordMeal
 	args := []interface{}{id}
 
-	createRaw(rep, selectMeal+findByID, "", "", args).Scan(&rec)
-	return convertToMeal(&rec)
+	Meals, err := findRows(rep, selectMeal+findByID, "", "", args)
+	if err != nil || len(Meals) == 0 {
+		return optional.None[*Meal]()
+	}
+	return optional.Some(&Meals[0])
+}
+
+// FindByUserAndDate returns the Meals which a given user has taken in a given day in order of time.
+// The day is from the midnight of the date to the next midnight in the time zone of the date.
+func (m *Meal) FindByUserAndDate(rep repository.Repository, user *User, date time.Time) ([]Meal, error) {
+	from := util.StartOfDay(date)
+	args := []interface{}{user.user_id, from.UTC(), from.AddDate(0, 0, 1).UTC()}
+	return findRows(rep, selectMeal+findByUserAndDate, "", "", args)
 }
 
 // FindAll returns all Meals of the Meal table.
@@ -73,23 +217,14 @@ func (m *Meal) FindAllByPage(rep repository.Repository, page string, size string
 	var Meals []Meal
 	var err error
 
-	if Meals, err = findRows(rep, selectMeal, page, size, []interface{}{}); err != nil {
+	total, err := countRows(rep, selectMeal, []interface{}{})
+	if err != nil {
 		return nil, err
 	}
-	p := createPage(&Meals, page, size)
-	return p, nil
-}
-
-// FindByName returns the page object of Meals partially matched given Meal title.
-func (b *Meal) FindByName(rep repository.Repository, name string, page string, size string) (*Page, error) {
-	var Meals []Meal
-	var err error
-	args := []interface{}{"%" + name + "%"}
-
-	if Meals, err = findRows(rep, selectMeal+findByName, page, size, args); err != nil {
+	if Meals, err = findRows(rep, selectMeal, page, size, []interface{}{}); err != nil {
 		return nil, err
 	}
-	p := createPage(&Meals, page, size)
+	p := createPage(&Meals, total, page, size)
 	return p, nil
 }
 
@@ -115,9 +250,43 @@ func findRows(rep repository.Repository, sqlquery string, page string, size stri
 		Meal, _ := opt.Take()
 		Meals = append(Meals, *Meal)
 	}
+	if err = loadItems(rep, Meals); err != nil {
+		return nil, err
+	}
 	return Meals, nil
 }
 
+// loadItems fetches the items of given Meals and computes the calories of them.
+func loadItems(rep repository.Repository, Meals []Meal) error {
+	if len(Meals) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(Meals))
+	for i := range Meals {
+		ids[i] = Meals[i].meal_id
+	}
+
+	item := MealItem{}
+	items, err := item.FindByMealIDs(rep, ids)
+	if err != nil {
+		return err
+	}
+	for i := range Meals {
+		Meals[i].setItems(items[Meals[i].meal_id])
+	}
+	return nil
+}
+
+func (m *Meal) setItems(items []MealItem) {
+	m.items = items
+	m.nutrients = *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)
+	for i := range items {
+		m.nutrients.add(&items[i].nutrients)
+	}
+	m.calories = m.nutrients.calories
+}
+
 func createRaw(rep repository.Repository, sql string, pageNum string, pageSize string, args []interface{}) *gorm.DB {
 	if util.IsNumeric(pageNum) && util.IsNumeric(pageSize) {
 		page := util.ConvertToInt(pageNum)
@@ -132,33 +301,236 @@ func createRaw(rep repository.Repository, sql string, pageNum string, pageSize s
 	return rep.Raw(sql)
 }
 
-func createPage(Meals *[]Meal, page string, size string) *Page {
+// countRows returns the number of the rows which given SQL finds regardless of the page.
+func countRows(rep repository.Repository, sqlquery string, args []interface{}) (int, error) {
+	var total int
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = createRaw(rep, fmt.Sprintf(countRowsSQL, sqlquery), "", "", args).Rows(); err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, rows.Err()
+}
+
+// createPage returns the page object of given content. The total is the number of the elements of all pages.
+// All elements are in one page if the page or the size is not given.
+func createPage[T any](content *[]T, total int, page string, size string) *Page {
 	p := NewPage()
 	p.Page = util.ConvertToInt(page)
 	p.Size = util.ConvertToInt(size)
-	p.NumberOfElements = p.Size
-	p.TotalElements = len(*Meals)
-	if p.TotalPages = int(math.Ceil(float64(p.TotalElements) / float64(p.Size))); p.TotalPages < 0 {
-		p.TotalPages = 0
+	p.NumberOfElements = len(*content)
+	p.TotalElements = total
+	if p.Size > 0 {
+		p.TotalPages = int(math.Ceil(float64(p.TotalElements) / float64(p.Size)))
+	} else if p.TotalElements > 0 {
+		p.TotalPages = 1
 	}
-	p.Content = Meals
+	p.Last = p.Page+1 >= p.TotalPages
+	p.Content = content
 
 	return p
 }
 
+// IsOwnedBy returns true if this Meal belongs to a given user.
+func (m *Meal) IsOwnedBy(user *User) bool {
+	return user != nil && m.user_id == user.user_id
+}
+
+// CopyTo returns the new Meal which has the same name and items as this Meal at the same time of a given day.
+// The time of day is taken in the time zone of the day. It is not persisted yet.
+func (m *Meal) CopyTo(date time.Time) *Meal {
+	at := m.meal_at.In(date.Location())
+	meal_at := time.Date(date.Year(), date.Month(), date.Day(), at.Hour(), at.Minute(), at.Second(), 0, date.Location())
+	items := make([]MealItem, len(m.items))
+	for i := range m.items {
+		items[i] = *NewMealItem(m.items[i].food_id, m.items[i].quantity, m.items[i].unit)
+		items[i].recipe_version_id = m.items[i].recipe_version_id
+	}
+	return NewMeal(m.meal_name, &User{user_id: m.user_id}, meal_at, items, nil).SetMealType(m.meal_type)
+}
+
 // Save persists this Meal data.
 func (m *Meal) Save(rep repository.Repository) (*Meal, error) {
 	if err := rep.Save(m).Error; err != nil {
 		return nil, err
 	}
+	return m, nil
+}
+
+// Create persists this Meal data and its items with the snapshots of the nutrients of their foods.
+// The meal type is inferred from the time of day in the time zone of the user if it is empty.
+// The current versions of the Recipes are expanded into the items.
+// The Meal belongs to given user, whose private foods and Recipes can be used besides the global ones.
+// The foods are checked against the restrictions of the user. The conflicts are set as the warnings,
+// or a RestrictionError is returned without persisting anything if the user rejects such Meals.
+func (b *Meal) Create(rep repository.Repository, user *User) (*Meal, error) {
+	b.user_id = user.user_id
+	if err := user.LoadRestrictions(rep); err != nil {
+		return nil, err
+	}
+	if b.meal_type == "" {
+		b.meal_type = mealTypeAt(b.meal_at.In(user.Location(rep)))
+	}
+	b.meal_at = b.meal_at.UTC()
+
+	foods, err := b.prepareItems(rep, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rep.Select("user_id", "meal_name", "meal_at", "meal_type").Create(b).Error; err != nil {
+		return nil, err
+	}
+	if err := b.createItems(rep, foods); err != nil {
+		return nil, err
+	}
+	b.setItems(b.items)
+	return b, nil
+}
+
+// Update replaces the name, the time and the meal type of this Meal with those of given Meal.
+// The items are replaced with the items and the Recipes of given Meal in the same way as Create,
+// or kept as they are with their snapshots if given Meal has neither of them.
+// Given user must own this Meal, and the private foods and Recipes of the user can be used.
+func (b *Meal) Update(rep repository.Repository, user *User, meal *Meal) (*Meal, error) {
+	if !b.IsOwnedBy(user) {
+		return nil, ErrMealNotOwned
+	}
+	if err := user.LoadRestrictions(rep); err != nil {
+		return nil, err
+	}
+	b.meal_name, b.meal_at, b.meal_type = meal.meal_name, meal.meal_at, meal.meal_type
+	if b.meal_type == "" {
+		b.meal_type = mealTypeAt(b.meal_at.In(user.Location(rep)))
+	}
+	b.meal_at = b.meal_at.UTC()
+
+	replace := len(meal.items) > 0 || len(meal.recipes) > 0
+	var foods []*Food
+	if replace {
+		b.items, b.recipes = meal.items, meal.recipes
+		var err error
+		if foods, err = b.prepareItems(rep, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := rep.Model(b).Select("meal_name", "meal_at", "meal_type").Updates(b).Error; err != nil {
+		return nil, err
+	}
+	if replace {
+		if err := b.deleteItems(rep); err != nil {
+			return nil, err
+		}
+		if err := b.createItems(rep, foods); err != nil {
+			return nil, err
+		}
+	}
+	b.setItems(b.items)
 	return b, nil
 }
 
-// Create persists this Meal data.
-func (b *Meal) Create(rep repository.Repository) (*Meal, error) {
-	if err := rep.Select("user_id", "food_id", "meal_name").Create(b).Error; err != nil {
+// Delete deletes this Meal data and its items. The PlannedMeal which was logged as this Meal is planned again.
+func (b *Meal) Delete(rep repository.Repository) (*Meal, error) {
+	if err := b.deleteItems(rep); err != nil {
 		return nil, err
 	}
+	if err := rep.Model(&PlannedMeal{}).Where("meal_id = ?", b.meal_id).
+		Updates(map[string]interface{}{"meal_id": nil, "status": PlanStatusPlanned}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Delete(b).Error; err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// prepareItems expands the Recipes into the items and finds the foods of the items which given user can see.
+// The foods are checked against the restrictions of the user and the conflicts are set as the warnings.
+// It returns a RestrictionError if the user rejects such Meals.
+func (b *Meal) prepareItems(rep repository.Repository, user *User) ([]*Food, error) {
+	recipe := Recipe{}
+	for _, mr := range b.recipes {
+		r, err := recipe.FindByID(rep, user, mr.recipe_id, 0).Take()
+		if err != nil {
+			return nil, fmt.Errorf("recipe %d is not found", mr.recipe_id)
+		}
+		b.items = append(b.items, r.mealItems(mr.servings)...)
+	}
+
+	food := Food{}
+	foods := make([]*Food, len(b.items))
+	checked := make(map[uint]bool)
+	b.warnings = nil
+	for i := range b.items {
+		if b.items[i].food_id == 0 {
+			f, err := b.items[i].resolveBarcode(rep)
+			if err != nil {
+				return nil, err
+			}
+			b.items[i].food_id = f.food_id
+		}
+		f, err := food.FindVisibleByID(rep, user, b.items[i].food_id).Take()
+		if err != nil {
+			return nil, fmt.Errorf("food %d is not found", b.items[i].food_id)
+		}
+		if _, err = f.grams(b.items[i].quantity, b.items[i].unit); err != nil {
+			return nil, err
+		}
+		foods[i] = f
+		if !checked[f.food_id] {
+			checked[f.food_id] = true
+			b.warnings = append(b.warnings, user.conflicts(f)...)
+		}
+	}
+	if len(b.warnings) > 0 && user.restriction_mode == RestrictionModeReject {
+		return nil, &RestrictionError{Conflicts: b.warnings}
+	}
+	return foods, nil
+}
+
+// createItems persists the items of this Meal with the snapshots of the nutrients of given foods.
+func (b *Meal) createItems(rep repository.Repository, foods []*Food) error {
+	for i := range b.items {
+		b.items[i].meal_id = b.meal_id
+		if _, err := b.items[i].Create(rep, foods[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteItems deletes the items of this Meal and their snapshots of the micronutrients.
+func (b *Meal) deleteItems(rep repository.Repository) error {
+	if err := rep.Where("meal_item_id in (?)", rep.Model(&MealItem{}).Select("meal_item_id").Where("meal_id = ?", b.meal_id)).
+		Delete(&MealItemNutrient{}).Error; err != nil {
+		return err
+	}
+	return rep.Where("meal_id = ?", b.meal_id).Delete(&MealItem{}).Error
+}
+
+// Recalculate replaces the snapshots of the nutrients of the items of this Meal
+// with the current nutrients and sizes of their foods.
+func (b *Meal) Recalculate(rep repository.Repository) (*Meal, error) {
+	food := Food{}
+	for i := range b.items {
+		f, err := food.FindByID(rep, b.items[i].food_id).Take()
+		if err != nil {
+			return nil, fmt.Errorf("food %d is not found", b.items[i].food_id)
+		}
+		if _, err := b.items[i].Recalculate(rep, f); err != nil {
+			return nil, err
+		}
+	}
+	b.setItems(b.items)
 	return b, nil
 }
 
@@ -166,9 +538,9 @@ func convertToMeal(rec *RecordMeal) optional.Option[*Meal] {
 	if rec.meal_id == 0 {
 		return optional.None[*Meal]()
 	}
-	f := &Food{food_id: rec.food_id, food_name: rec.food_name}
 	return optional.Some(
-		&Meal{meal_id: rec.meal_id, meal_name: rec.meal_name, user_id: rec.user_id, food_id: rec.food_id, Food: f})
+		&Meal{meal_id: rec.meal_id, meal_name: rec.meal_name, user_id: rec.user_id, meal_at: rec.meal_at, meal_type: rec.meal_type,
+			items: []MealItem{}})
 }
 
 // ToString is return string of object
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+const (
+	// MealBatchCreate represents the operation which creates a Meal.
+	MealBatchCreate = "create"
+	// MealBatchUpdate represents the operation which replaces an existing Meal.
+	MealBatchUpdate = "update"
+	// MealBatchDelete represents the operation which deletes an existing Meal.
+	MealBatchDelete = "delete"
+)
+
+const (
+	// MealBatchAtomic represents the batch which applies all operations or none of them.
+	MealBatchAtomic = "atomic"
+	// MealBatchBestEffort represents the batch which applies the operations which succeed and skips the others.
+	MealBatchBestEffort = "best_effort"
+)
+
+const (
+	// MealBatchSucceeded represents the operation which has been applied.
+	MealBatchSucceeded = "succeeded"
+	// MealBatchFailed represents the operation which is invalid or has failed.
+	MealBatchFailed = "failed"
+	// MealBatchSkipped represents the operation which has not been tried because the atomic batch has failed.
+	MealBatchSkipped = "skipped"
+	// MealBatchRolledBack represents the operation which has succeeded but is undone because the atomic batch has failed.
+	MealBatchRolledBack = "rolled_back"
+)
+
+// MealBatchResult defines struct of the result of the operations on the Meals in a batch.
+// The results of the operations are in the same order as the operations, and their index is that of the operation.
+type MealBatchResult struct {
+	mode       string                     `json:"mode"`
+	succeeded  int                        `json:"succeeded"`
+	failed     int                        `json:"failed"`
+	operations []MealBatchOperationResult `json:"operations"`
+}
+
+// MealBatchOperationResult defines struct of the result of an operation in a batch.
+// The errors are the validation errors or the reason of the failure keyed by the field.
+type MealBatchOperationResult struct {
+	index  int               `json:"index"`
+	op     string            `json:"op"`
+	status string            `json:"status"`
+	meal   *Meal             `json:"meal,omitempty"`
+	errors map[string]string `json:"errors,omitempty"`
+}
+
+// NewMealBatchResult is constructor. All of given operations are skipped until they succeed or fail.
+func NewMealBatchResult(mode string, ops []string) *MealBatchResult {
+	r := &MealBatchResult{mode: mode, operations: make([]MealBatchOperationResult, len(ops))}
+	for i := range ops {
+		r.operations[i] = MealBatchOperationResult{index: i, op: ops[i], status: MealBatchSkipped}
+	}
+	return r
+}
+
+// IsAtomic returns true if this batch applies all operations or none of them.
+func (r *MealBatchResult) IsAtomic() bool {
+	return r.mode != MealBatchBestEffort
+}
+
+// HasFailures returns true if any operation of this batch has failed.
+func (r *MealBatchResult) HasFailures() bool {
+	return r.failed > 0
+}
+
+// Succeed records that the operation of given index has been applied to given Meal.
+func (r *MealBatchResult) Succeed(index int, meal *Meal) {
+	r.operations[index].status = MealBatchSucceeded
+	r.operations[index].meal = meal
+	r.succeeded++
+}
+
+// Fail records that the operation of given index is invalid or has failed for given reasons.
+func (r *MealBatchResult) Fail(index int, errors map[string]string) {
+	r.operations[index].status = MealBatchFailed
+	r.operations[index].errors = errors
+	r.failed++
+}
+
+// RollBack records that the operations which have succeeded are undone.
+func (r *MealBatchResult) RollBack() {
+	for i := range r.operations {
+		if r.operations[i].status == MealBatchSucceeded {
+			r.operations[i].status = MealBatchRolledBack
+			r.operations[i].meal = nil
+		}
+	}
+	r.succeeded = 0
+}
+
+// Apply applies the operations of this batch which have not failed by given function in a transaction,
+// and records their results. The errors of the failed operations are converted by given function.
+// In the atomic mode, the transaction is rolled back at the first failure and the succeeded operations are rolled back.
+// In the best_effort mode, each operation is applied under its own save point, so that the failed ones are undone alone.
+// It returns an error only if the transaction itself fails.
+func (r *MealBatchResult) Apply(rep repository.Repository,
+	apply func(txrep repository.Repository, index int) (*Meal, error),
+	messages func(index int, err error) map[string]string) error {
+	var failure error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		for i := range r.operations {
+			if r.operations[i].status == MealBatchFailed {
+				continue
+			}
+			savepoint := fmt.Sprintf("meal_batch_%d", i)
+			if !r.IsAtomic() {
+				if err := txrep.SavePoint(savepoint); err != nil {
+					return err
+				}
+			}
+			meal, err := apply(txrep, i)
+			if err == nil {
+				r.Succeed(i, meal)
+				continue
+			}
+			r.Fail(i, messages(i, err))
+			if r.IsAtomic() {
+				failure = err
+				return err
+			}
+			if err := txrep.RollbackTo(savepoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); trerr != nil {
+		if failure != nil && errors.Is(trerr, failure) {
+			r.RollBack()
+			return nil
+		}
+		return trerr
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// batchRepository records the transaction and the save points which a batch goes through.
+type batchRepository struct {
+	repository.Repository
+	calls        []string
+	savePointErr error
+}
+
+func (rep *batchRepository) Transaction(fc func(tx repository.Repository) error) error {
+	if err := fc(rep); err != nil {
+		rep.calls = append(rep.calls, "rollback")
+		return err
+	}
+	rep.calls = append(rep.calls, "commit")
+	return nil
+}
+
+func (rep *batchRepository) SavePoint(name string) error {
+	rep.calls = append(rep.calls, "savepoint "+name)
+	return rep.savePointErr
+}
+
+func (rep *batchRepository) RollbackTo(name string) error {
+	rep.calls = append(rep.calls, "rollback to "+name)
+	return nil
+}
+
+func (r *MealBatchResult) statuses() []string {
+	statuses := make([]string, len(r.operations))
+	for i := range r.operations {
+		statuses[i] = r.operations[i].status
+	}
+	return statuses
+}
+
+var errMealGone = errors.New("meal is gone")
+
+// applyFailing returns the function which applies the operations, failing those of given indexes.
+func applyFailing(failing ...int) (func(repository.Repository, int) (*Meal, error), *[]int) {
+	applied := []int{}
+	return func(_ repository.Repository, index int) (*Meal, error) {
+		applied = append(applied, index)
+		for _, i := range failing {
+			if i == index {
+				return nil, errMealGone
+			}
+		}
+		return &Meal{meal_id: uint(index + 1)}, nil
+	}, &applied
+}
+
+func batchMessages(_ int, err error) map[string]string {
+	return map[string]string{"error": err.Error()}
+}
+
+func TestMealBatchBestEffortRollsBackToSavePoint(t *testing.T) {
+	rep := &batchRepository{}
+	result := NewMealBatchResult(MealBatchBestEffort, []string{MealBatchCreate, MealBatchUpdate, MealBatchDelete, MealBatchCreate})
+	result.Fail(1, map[string]string{"meal_name": "required"})
+	apply, applied := applyFailing(2)
+
+	if err := result.Apply(rep, apply, batchMessages); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// The invalid operation is not tried, and only the failed one is undone before the transaction is committed.
+	wantCalls := []string{"savepoint meal_batch_0", "savepoint meal_batch_2", "rollback to meal_batch_2", "savepoint meal_batch_3", "commit"}
+	if !reflect.DeepEqual(rep.calls, wantCalls) {
+		t.Errorf("calls = %v, want %v", rep.calls, wantCalls)
+	}
+	if !reflect.DeepEqual(*applied, []int{0, 2, 3}) {
+		t.Errorf("applied = %v, want [0 2 3]", *applied)
+	}
+	wantStatuses := []string{MealBatchSucceeded, MealBatchFailed, MealBatchFailed, MealBatchSucceeded}
+	if got := result.statuses(); !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("statuses = %v, want %v", got, wantStatuses)
+	}
+	if result.succeeded != 2 || result.failed != 2 {
+		t.Errorf("succeeded, failed = %d, %d, want 2, 2", result.succeeded, result.failed)
+	}
+	if result.operations[2].errors["error"] != errMealGone.Error() || result.operations[3].meal.meal_id != 4 {
+		t.Errorf("operations = %+v, want the error of 2 and the meal of 3", result.operations)
+	}
+}
+
+func TestMealBatchAtomicRollsBackEverything(t *testing.T) {
+	rep := &batchRepository{}
+	result := NewMealBatchResult(MealBatchAtomic, []string{MealBatchCreate, MealBatchUpdate, MealBatchDelete})
+	apply, applied := applyFailing(1)
+
+	if err := result.Apply(rep, apply, batchMessages); err != nil {
+		t.Fatalf("Apply() error = %v, want nil for a failed operation", err)
+	}
+
+	if !reflect.DeepEqual(rep.calls, []string{"rollback"}) {
+		t.Errorf("calls = %v, want only the rollback of the transaction without save points", rep.calls)
+	}
+	if !reflect.DeepEqual(*applied, []int{0, 1}) {
+		t.Errorf("applied = %v, want to stop at the failure", *applied)
+	}
+	wantStatuses := []string{MealBatchRolledBack, MealBatchFailed, MealBatchSkipped}
+	if got := result.statuses(); !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("statuses = %v, want %v", got, wantStatuses)
+	}
+	if result.succeeded != 0 || result.operations[0].meal != nil {
+		t.Errorf("succeeded = %d, meal = %v, want no meal to remain", result.succeeded, result.operations[0].meal)
+	}
+}
+
+func TestMealBatchFailsWithTransaction(t *testing.T) {
+	rep := &batchRepository{savePointErr: errors.New("savepoint is not supported")}
+	result := NewMealBatchResult(MealBatchBestEffort, []string{MealBatchCreate})
+	apply, applied := applyFailing()
+
+	if err := result.Apply(rep, apply, batchMessages); !errors.Is(err, rep.savePointErr) {
+		t.Fatalf("Apply() error = %v, want %v", err, rep.savePointErr)
+	}
+	if len(*applied) != 0 {
+		t.Errorf("applied = %v, want none without the save point", *applied)
+	}
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// MealCursor defines struct of a position in the Meal list ordered by meal_at and meal_id, the newest first.
+// It is encoded into an opaque string which the clients pass back to fetch the next or the previous page.
+// The meal_at is kept in UTC in the same way as the stored Meals.
+type MealCursor struct {
+	meal_at time.Time
+	meal_id uint
+	before  bool
+}
+
+const (
+	// defaultCursorSize is the number of the Meals per page fetched by a cursor when the size is not given.
+	defaultCursorSize = 20
+	// mealCursorFormat is the format of the decoded cursor, the direction, meal_at in Unix nanoseconds and meal_id.
+	mealCursorFormat = "%s:%d:%d"
+	cursorNext       = "n"
+	cursorPrev       = "p"
+	// filterMealsAfter is the condition of the Meals older than a cursor, and filterMealsBefore is of the newer ones.
+	// They are expanded instead of the row value comparison so that every database can use the index.
+	filterMealsAfter  = " and (m.meal_at < ? or (m.meal_at = ? and m.meal_id < ?)) order by m.meal_at desc, m.meal_id desc limit ?"
+	filterMealsBefore = " and (m.meal_at > ? or (m.meal_at = ? and m.meal_id > ?)) order by m.meal_at, m.meal_id limit ?"
+	orderMealsByKey   = " order by m.meal_at desc, m.meal_id desc limit ?"
+)
+
+// ErrInvalidCursor is returned when the cursor was not issued by this application.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrCursorSort is returned when the Meals are fetched by a cursor in the order other than the newest first.
+var ErrCursorSort = errors.New("the cursor cannot be used with the sort fields")
+
+// newMealCursor returns the cursor of the page next to given Meal, or previous to it if before is true.
+func newMealCursor(meal *Meal, before bool) *MealCursor {
+	return &MealCursor{meal_at: meal.meal_at.UTC(), meal_id: meal.meal_id, before: before}
+}
+
+// DecodeMealCursor returns the MealCursor of given opaque string. It returns ErrInvalidCursor if the string is malformed.
+func DecodeMealCursor(cursor string) (*MealCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var direction string
+	var nanos int64
+	var meal_id uint
+	if _, err = fmt.Sscanf(string(decoded), "%1s:%d:%d", &direction, &nanos, &meal_id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if direction != cursorNext && direction != cursorPrev {
+		return nil, ErrInvalidCursor
+	}
+	return &MealCursor{meal_at: time.Unix(0, nanos).UTC(), meal_id: meal_id, before: direction == cursorPrev}, nil
+}
+
+// Encode returns the opaque string of this MealCursor.
+func (c *MealCursor) Encode() string {
+	direction := cursorNext
+	if c.before {
+		direction = cursorPrev
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(mealCursorFormat, direction, c.meal_at.UnixNano(), c.meal_id)))
+}
+
+// FindByCursor returns the page object of the Meals matched a given MealFilter next to a given cursor, the newest first.
+// The nil cursor means the first page. The Meals are sought by the index of meal_at and meal_id instead of the offset,
+// so that the deep pages are fetched as fast as the first one. The MealFilter must not have the fields to sort by.
+func (m *Meal) FindByCursor(rep repository.Repository, filter *MealFilter, cursor *MealCursor, size string) (*Page, error) {
+	if len(filter.sorts) > 0 {
+		return nil, ErrCursorSort
+	}
+	limit := defaultCursorSize
+	if n := util.ConvertToInt(size); n > 0 {
+		limit = n
+	}
+
+	where, args := filter.where()
+	total, err := countRows(rep, where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlquery := where + orderMealsByKey
+	switch {
+	case cursor == nil:
+		args = append(args, limit+1)
+	case cursor.before:
+		sqlquery = where + filterMealsBefore
+		args = append(args, cursor.meal_at, cursor.meal_at, cursor.meal_id, limit+1)
+	default:
+		sqlquery = where + filterMealsAfter
+		args = append(args, cursor.meal_at, cursor.meal_at, cursor.meal_id, limit+1)
+	}
+	Meals, err := findRows(rep, sqlquery, "", "", args)
+	if err != nil {
+		return nil, err
+	}
+
+	// One more Meal than the limit is fetched to know whether there are more pages in the direction.
+	more := len(Meals) > limit
+	if more {
+		Meals = Meals[:limit]
+	}
+	if cursor != nil && cursor.before {
+		for i, j := 0, len(Meals)-1; i < j; i, j = i+1, j-1 {
+			Meals[i], Meals[j] = Meals[j], Meals[i]
+		}
+	}
+
+	p := createPage(&Meals, total, "", strconv.Itoa(limit))
+	p.Next, p.Prev = cursorLinks(Meals, cursor, more)
+	p.Last = p.Next == ""
+	return p, nil
+}
+
+// cursorLinks returns the cursors of the pages next to and previous to given page of the Meals fetched by given cursor.
+// The more is true if there are more Meals beyond the page in the direction of the cursor.
+// The cursor is empty if there is no such page.
+func cursorLinks(Meals []Meal, cursor *MealCursor, more bool) (string, string) {
+	if len(Meals) == 0 {
+		return "", ""
+	}
+	var next, prev string
+	backward := cursor != nil && cursor.before
+	if more || backward {
+		next = newMealCursor(&Meals[len(Meals)-1], false).Encode()
+	}
+	if (more && backward) || (cursor != nil && !backward) {
+		prev = newMealCursor(&Meals[0], true).Encode()
+	}
+	return next, prev
+}
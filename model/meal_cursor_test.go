@@ -0,0 +1,235 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMealCursorRoundTrip(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	cases := []struct {
+		name   string
+		cursor MealCursor
+	}{
+		{"utc next", MealCursor{meal_at: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), meal_id: 1}},
+		{"utc prev", MealCursor{meal_at: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), meal_id: 1, before: true}},
+		{"positive offset", MealCursor{meal_at: time.Date(2024, 3, 1, 8, 30, 0, 0, tokyo), meal_id: 42}},
+		{"negative offset", MealCursor{meal_at: time.Date(2024, 11, 3, 1, 30, 0, 0, newYork), meal_id: 7, before: true}},
+		{"nanoseconds", MealCursor{meal_at: time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC), meal_id: 99}},
+		{"large id", MealCursor{meal_at: time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC), meal_id: 4294967295}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := DecodeMealCursor(tc.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeMealCursor() error = %v", err)
+			}
+			if !decoded.meal_at.Equal(tc.cursor.meal_at) {
+				t.Errorf("meal_at = %v, want %v", decoded.meal_at, tc.cursor.meal_at)
+			}
+			if decoded.meal_at.Location() != time.UTC {
+				t.Errorf("meal_at is in %v, want UTC", decoded.meal_at.Location())
+			}
+			if decoded.meal_id != tc.cursor.meal_id {
+				t.Errorf("meal_id = %d, want %d", decoded.meal_id, tc.cursor.meal_id)
+			}
+			if decoded.before != tc.cursor.before {
+				t.Errorf("before = %v, want %v", decoded.before, tc.cursor.before)
+			}
+		})
+	}
+}
+
+func TestDecodeMealCursorInvalid(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"unknown direction", encode("x:1:2")},
+		{"not a time", encode("n:abc:2")},
+		{"no id", encode("n:1")},
+		{"negative id", encode("p:1:-2")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := DecodeMealCursor(tc.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeMealCursor(%q) error = %v, want ErrInvalidCursor", tc.cursor, err)
+			}
+		})
+	}
+}
+
+func TestCursorLinks(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	meals := []Meal{
+		{meal_id: 3, meal_at: at},
+		{meal_id: 2, meal_at: at.Add(-time.Hour)},
+		{meal_id: 1, meal_at: at.Add(-2 * time.Hour)},
+	}
+	after := &MealCursor{meal_at: at.Add(time.Hour), meal_id: 4}
+	before := &MealCursor{meal_at: at.Add(-3 * time.Hour), meal_id: 0, before: true}
+
+	cases := []struct {
+		name     string
+		meals    []Meal
+		cursor   *MealCursor
+		more     bool
+		wantNext bool
+		wantPrev bool
+	}{
+		{"first page with more", meals, nil, true, true, false},
+		{"only page", meals, nil, false, false, false},
+		{"middle page forward", meals, after, true, true, true},
+		{"last page forward", meals, after, false, false, true},
+		{"middle page backward", meals, before, true, true, true},
+		{"first page backward", meals, before, false, true, false},
+		{"empty page", nil, after, false, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next, prev := cursorLinks(tc.meals, tc.cursor, tc.more)
+			if (next != "") != tc.wantNext {
+				t.Fatalf("next = %q, want present %v", next, tc.wantNext)
+			}
+			if (prev != "") != tc.wantPrev {
+				t.Fatalf("prev = %q, want present %v", prev, tc.wantPrev)
+			}
+			if next != "" {
+				c, err := DecodeMealCursor(next)
+				if err != nil {
+					t.Fatalf("DecodeMealCursor(next) error = %v", err)
+				}
+				last := tc.meals[len(tc.meals)-1]
+				if c.before || c.meal_id != last.meal_id || !c.meal_at.Equal(last.meal_at) {
+					t.Errorf("next = %+v, want after the last meal %d", c, last.meal_id)
+				}
+			}
+			if prev != "" {
+				c, err := DecodeMealCursor(prev)
+				if err != nil {
+					t.Fatalf("DecodeMealCursor(prev) error = %v", err)
+				}
+				first := tc.meals[0]
+				if !c.before || c.meal_id != first.meal_id || !c.meal_at.Equal(first.meal_at) {
+					t.Errorf("prev = %+v, want before the first meal %d", c, first.meal_id)
+				}
+			}
+		})
+	}
+}
+
+// TestCursorPagingTies walks the Meals with the keyset conditions of FindByCursor on SQLite,
+// where several Meals share meal_at across the page boundaries, and checks no Meal is skipped or repeated.
+func TestCursorPagingTies(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := db.Exec("create table meals (meal_id integer primary key, meal_at datetime)").Error; err != nil {
+		t.Fatalf("create table error = %v", err)
+	}
+
+	noon := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	times := map[uint]time.Time{
+		1: noon.Add(-time.Hour),
+		2: noon, 3: noon, 4: noon, 5: noon,
+		6: noon.Add(time.Hour),
+	}
+	for id, at := range times {
+		if err := db.Exec("insert into meals (meal_id, meal_at) values (?, ?)", id, at).Error; err != nil {
+			t.Fatalf("insert error = %v", err)
+		}
+	}
+
+	const limit = 2
+	fetch := func(cursor *MealCursor) ([]Meal, bool) {
+		sqlquery := "select meal_id, meal_at from meals m where 1 = 1"
+		var args []interface{}
+		switch {
+		case cursor == nil:
+			sqlquery += orderMealsByKey
+		case cursor.before:
+			sqlquery += filterMealsBefore
+			args = append(args, cursor.meal_at, cursor.meal_at, cursor.meal_id)
+		default:
+			sqlquery += filterMealsAfter
+			args = append(args, cursor.meal_at, cursor.meal_at, cursor.meal_id)
+		}
+		var rows []struct {
+			MealID uint
+			MealAt time.Time
+		}
+		if err := db.Raw(sqlquery, append(args, limit+1)...).Scan(&rows).Error; err != nil {
+			t.Fatalf("select error = %v", err)
+		}
+		var meals []Meal
+		for _, row := range rows {
+			meals = append(meals, Meal{meal_id: row.MealID, meal_at: row.MealAt})
+		}
+		more := len(meals) > limit
+		if more {
+			meals = meals[:limit]
+		}
+		if cursor != nil && cursor.before {
+			for i, j := 0, len(meals)-1; i < j; i, j = i+1, j-1 {
+				meals[i], meals[j] = meals[j], meals[i]
+			}
+		}
+		return meals, more
+	}
+	ids := func(meals []Meal) []uint {
+		var result []uint
+		for _, meal := range meals {
+			result = append(result, meal.meal_id)
+		}
+		return result
+	}
+
+	var pages [][]uint
+	var cursor *MealCursor
+	for {
+		meals, more := fetch(cursor)
+		pages = append(pages, ids(meals))
+		next, _ := cursorLinks(meals, cursor, more)
+		if next == "" {
+			break
+		}
+		if cursor, err = DecodeMealCursor(next); err != nil {
+			t.Fatalf("DecodeMealCursor(next) error = %v", err)
+		}
+	}
+	want := [][]uint{{6, 5}, {4, 3}, {2, 1}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Fatalf("forward pages = %v, want %v", pages, want)
+	}
+
+	// Walk back from the last page by the previous cursors.
+	last, _ := fetch(cursor)
+	pages = [][]uint{ids(last)}
+	_, prev := cursorLinks(last, cursor, false)
+	for prev != "" {
+		if cursor, err = DecodeMealCursor(prev); err != nil {
+			t.Fatalf("DecodeMealCursor(prev) error = %v", err)
+		}
+		meals, more := fetch(cursor)
+		pages = append([][]uint{ids(meals)}, pages...)
+		_, prev = cursorLinks(meals, cursor, more)
+	}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("backward pages = %v, want %v", pages, want)
+	}
+}
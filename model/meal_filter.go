@@ -0,0 +1,163 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// MealFilter defines struct of the conditions and the order of the Meals of a user to find.
+// The conditions and the order are translated into the SQL with the placeholders, and the columns to sort by are whitelisted.
+type MealFilter struct {
+	user_id      uint
+	query        string
+	from         *time.Time
+	to           *time.Time
+	food_id      *uint
+	meal_type    string
+	min_calories *float64
+	max_calories *float64
+	sorts        []mealSort
+}
+
+type mealSort struct {
+	column string
+	desc   bool
+}
+
+// mealCalories is the SQL expression of the calories of a Meal computed from the snapshots of its items.
+var mealCalories = "(select coalesce(sum(" + portionRatio + " * i.calo_amount), 0) from meal_items i where i.meal_id = m.meal_id)"
+
+// MealSortFields maps the fields which the Meals can be sorted by to the SQL expressions.
+var MealSortFields = map[string]string{
+	"id":        "m.meal_id",
+	"meal_name": "m.meal_name",
+	"meal_at":   "m.meal_at",
+	"meal_type": "m.meal_type",
+	"calories":  mealCalories,
+}
+
+const (
+	// defaultMealSort is the order of the Meals when no field to sort by is given, the newest first.
+	defaultMealSort = "m.meal_at desc"
+	// filterMealsByFood is the condition of the Meals which have an item of a food.
+	filterMealsByFood = "exists (select 1 from meal_items f where f.meal_id = m.meal_id and f.food_id = ?)"
+)
+
+// NewMealFilter is constructor of the filter of the Meals of a given user.
+func NewMealFilter(user *User) *MealFilter {
+	return &MealFilter{user_id: user.user_id}
+}
+
+// Name filters the Meals whose name partially matches a given query.
+func (f *MealFilter) Name(query string) *MealFilter {
+	f.query = query
+	return f
+}
+
+// Range filters the Meals taken from a given time until before a given time. The nil means no limit.
+func (f *MealFilter) Range(from *time.Time, to *time.Time) *MealFilter {
+	f.from, f.to = from, to
+	return f
+}
+
+// Food filters the Meals which have an item of a given Food.
+func (f *MealFilter) Food(food_id uint) *MealFilter {
+	f.food_id = &food_id
+	return f
+}
+
+// MealType filters the Meals of a given meal type.
+func (f *MealFilter) MealType(meal_type string) *MealFilter {
+	f.meal_type = meal_type
+	return f
+}
+
+// Calories filters the Meals whose calories are in a given range. The nil means no limit.
+func (f *MealFilter) Calories(min *float64, max *float64) *MealFilter {
+	f.min_calories, f.max_calories = min, max
+	return f
+}
+
+// SortBy appends a given field to sort the Meals by. It returns an error if the field is not in MealSortFields.
+func (f *MealFilter) SortBy(field string, desc bool) error {
+	column, ok := MealSortFields[field]
+	if !ok {
+		return fmt.Errorf("unknown sort field: %s", field)
+	}
+	f.sorts = append(f.sorts, mealSort{column: column, desc: desc})
+	return nil
+}
+
+// sql returns the SQL which finds the Meals by this MealFilter in its order and the arguments of its placeholders.
+func (f *MealFilter) sql() (string, []interface{}) {
+	sqlquery, args := f.where()
+	return sqlquery + " order by " + f.orderBy(), args
+}
+
+// where returns the SQL which finds the Meals by this MealFilter in no particular order and the arguments of its placeholders.
+func (f *MealFilter) where() (string, []interface{}) {
+	conditions := []string{"m.user_id = ?"}
+	args := []interface{}{f.user_id}
+	if f.query != "" {
+		conditions = append(conditions, "m.meal_name like ?")
+		args = append(args, "%"+f.query+"%")
+	}
+	if f.from != nil {
+		conditions = append(conditions, "m.meal_at >= ?")
+		args = append(args, f.from.UTC())
+	}
+	if f.to != nil {
+		conditions = append(conditions, "m.meal_at < ?")
+		args = append(args, f.to.UTC())
+	}
+	if f.food_id != nil {
+		conditions = append(conditions, filterMealsByFood)
+		args = append(args, *f.food_id)
+	}
+	if f.meal_type != "" {
+		conditions = append(conditions, "m.meal_type = ?")
+		args = append(args, f.meal_type)
+	}
+	if f.min_calories != nil {
+		conditions = append(conditions, mealCalories+" >= ?")
+		args = append(args, *f.min_calories)
+	}
+	if f.max_calories != nil {
+		conditions = append(conditions, mealCalories+" <= ?")
+		args = append(args, *f.max_calories)
+	}
+	return selectMeal + " where " + strings.Join(conditions, " and "), args
+}
+
+// orderBy returns the order by clause of this MealFilter. The ID breaks the ties so that the pages are stable.
+func (f *MealFilter) orderBy() string {
+	if len(f.sorts) == 0 {
+		return defaultMealSort + ", m.meal_id desc"
+	}
+	orders := make([]string, len(f.sorts))
+	for i, s := range f.sorts {
+		orders[i] = s.column
+		if s.desc {
+			orders[i] += " desc"
+		}
+	}
+	return strings.Join(orders, ", ") + ", m.meal_id"
+}
+
+// FindByFilter returns the page object of the Meals matched a given MealFilter.
+func (m *Meal) FindByFilter(rep repository.Repository, filter *MealFilter, page string, size string) (*Page, error) {
+	where, whereArgs := filter.where()
+	total, err := countRows(rep, where, whereArgs)
+	if err != nil {
+		return nil, err
+	}
+	sqlquery, args := filter.sql()
+	Meals, err := findRows(rep, sqlquery, page, size, args)
+	if err != nil {
+		return nil, err
+	}
+	return createPage(&Meals, total, page, size), nil
+}
@@ -0,0 +1,327 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+	"gorm.io/gorm"
+)
+
+const (
+	// UnitGram represents the quantity in grams.
+	UnitGram = "g"
+	// UnitServing represents the quantity in servings of a food.
+	UnitServing = util.UnitServing
+)
+
+// MealItem defines struct of a food and its portion in a Meal.
+// The portion in grams and the nutrients per 100 g of the food are snapshotted when the item is logged
+// or recalculated at calculated_at, so that editing the food does not change the past Meals.
+type MealItem struct {
+	meal_item_id      uint               `gorm:"primary_key" json:"id"`
+	meal_id           uint               `json:"meal_id"`
+	food_id           uint               `json:"food_id"`
+	quantity          float64            `json:"quantity"`
+	unit              string             `json:"unit"`
+	recipe_version_id *uint              `json:"recipe_version_id"`
+	grams             float64            `json:"grams"`
+	calo_amount       float64            `json:"-"`
+	protein           float64            `json:"-"`
+	carbohydrate      float64            `json:"-"`
+	fat               float64            `json:"-"`
+	fiber             float64            `json:"-"`
+	sugar             float64            `json:"-"`
+	sodium            float64            `json:"-"`
+	calculated_at     *time.Time         `json:"calculated_at"`
+	micronutrients    map[string]float64 `gorm:"-" json:"-"`
+	barcode           string             `gorm:"-" json:"-"`
+	food_name         string             `gorm:"-" json:"food_name"`
+	calories          float64            `gorm:"-" json:"calories"`
+	nutrients         Nutrients          `gorm:"-" json:"nutrients"`
+}
+
+// MealItemNutrient defines struct of the snapshot of the amount of a micronutrient per 100 g of the food of a MealItem.
+type MealItemNutrient struct {
+	meal_item_nutrient_id uint    `gorm:"primary_key" json:"id"`
+	meal_item_id          uint    `json:"meal_item_id"`
+	nutrient_name         string  `json:"nutrient_name"`
+	amount                float64 `json:"amount"`
+}
+
+// UnitError is returned when a portion of a food cannot be converted to grams.
+type UnitError struct {
+	Food string
+	Unit string
+	Err  error
+}
+
+// RecordMealItem defines struct represents the record of the database.
+type RecordMealItem struct {
+	meal_item_id      uint
+	meal_id           uint
+	food_id           uint
+	quantity          float64
+	unit              string
+	recipe_version_id *uint
+	food_name         string
+	grams             float64
+	calculated_at     *time.Time
+	calo_amount       float64
+	protein           float64
+	carbohydrate      float64
+	fat               float64
+	fiber             float64
+	sugar             float64
+	sodium            float64
+}
+
+const (
+	selectMealItem = "select i.meal_item_id as meal_item_id, i.meal_id as meal_id, i.food_id as food_id, " +
+		"i.quantity as quantity, i.unit as unit, i.recipe_version_id as recipe_version_id, f.food_name as food_name, " +
+		"i.grams as grams, i.calculated_at as calculated_at, i.calo_amount as calo_amount, i.protein as protein, " +
+		"i.carbohydrate as carbohydrate, i.fat as fat, i.fiber as fiber, i.sugar as sugar, i.sodium as sodium " +
+		"from meal_items i inner join foods f on f.food_id = i.food_id"
+	findItemsByMealIDs = " where i.meal_id in ? order by i.meal_item_id"
+	// portionRatio is the SQL expression of the ratio of an item's portion to 100 g, see calculate.
+	portionRatio = "(i.grams / 100)"
+	// snapshotMealItemNutrients copies the micronutrients of the foods to the items which have no snapshot.
+	snapshotMealItemNutrients = "insert into meal_item_nutrients (meal_item_id, nutrient_name, amount) " +
+		"select i.meal_item_id, n.nutrient_name, n.amount from meal_items i inner join food_nutrients n on n.food_id = i.food_id " +
+		"where i.calculated_at is null"
+	// snapshotMealItemColumn is the SQL expression of a column of the food of an item in the update of the items.
+	snapshotMealItemColumn = "(select %s from foods f where f.food_id = meal_items.food_id)"
+)
+
+// TableName returns the table name of MealItem struct and it is used by gorm.
+func (MealItem) TableName() string {
+	return "meal_items"
+}
+
+// TableName returns the table name of MealItemNutrient struct and it is used by gorm.
+func (MealItemNutrient) TableName() string {
+	return "meal_item_nutrients"
+}
+
+// NewMealItem is constructor
+func NewMealItem(food_id uint, quantity float64, unit string) *MealItem {
+	return &MealItem{food_id: food_id, quantity: quantity, unit: unit}
+}
+
+// NewScannedMealItem is constructor of the item whose food is identified by the barcode of a packaged food.
+func NewScannedMealItem(barcode string, quantity float64, unit string) *MealItem {
+	return &MealItem{barcode: barcode, quantity: quantity, unit: unit}
+}
+
+// FindByMealIDs returns the items of given Meals grouped by the Meal's ID.
+func (i *MealItem) FindByMealIDs(rep repository.Repository, meal_ids []uint) (map[uint][]MealItem, error) {
+	result := make(map[uint][]MealItem)
+
+	var recs []RecordMealItem
+	var rec RecordMealItem
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(selectMealItem+findItemsByMealIDs, meal_ids).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var item_ids []uint
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+		item_ids = append(item_ids, rec.meal_item_id)
+	}
+
+	micronutrients, err := findMealItemNutrients(rep, item_ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range recs {
+		item := MealItem{meal_item_id: rec.meal_item_id, meal_id: rec.meal_id, food_id: rec.food_id,
+			quantity: rec.quantity, unit: rec.unit, recipe_version_id: rec.recipe_version_id, calculated_at: rec.calculated_at}
+		item.setSnapshot(NewNutrients(rec.calo_amount, rec.protein, rec.carbohydrate, rec.fat,
+			rec.fiber, rec.sugar, rec.sodium, micronutrients[rec.meal_item_id]))
+		item.calculate(rec.food_name, item.per100g(), rec.grams)
+		result[rec.meal_id] = append(result[rec.meal_id], item)
+	}
+	return result, nil
+}
+
+// findMealItemNutrients returns the snapshots of the micronutrients of given items grouped by the item's ID.
+func findMealItemNutrients(rep repository.Repository, item_ids []uint) (map[uint]map[string]float64, error) {
+	result := make(map[uint]map[string]float64)
+	if len(item_ids) == 0 {
+		return result, nil
+	}
+
+	var nutrients []MealItemNutrient
+	if err := rep.Where("meal_item_id in ?", item_ids).Find(&nutrients).Error; err != nil {
+		return nil, err
+	}
+	for i := range nutrients {
+		if _, ok := result[nutrients[i].meal_item_id]; !ok {
+			result[nutrients[i].meal_item_id] = make(map[string]float64)
+		}
+		result[nutrients[i].meal_item_id][nutrients[i].nutrient_name] = nutrients[i].amount
+	}
+	return result, nil
+}
+
+// Create persists this MealItem data with the snapshot of the nutrients of given Food.
+func (i *MealItem) Create(rep repository.Repository, food *Food) (*MealItem, error) {
+	grams, err := food.grams(i.quantity, i.unit)
+	if err != nil {
+		return nil, err
+	}
+	i.snapshot(food, grams)
+	if err := rep.Select("meal_id", "food_id", "quantity", "unit", "recipe_version_id", "grams", "calo_amount", "protein",
+		"carbohydrate", "fat", "fiber", "sugar", "sodium", "calculated_at").Create(i).Error; err != nil {
+		return nil, err
+	}
+	if err := i.createNutrients(rep); err != nil {
+		return nil, err
+	}
+	i.calculate(food.food_name, i.per100g(), grams)
+	return i, nil
+}
+
+// Recalculate replaces the snapshot of this MealItem with the current nutrients and sizes of given Food.
+func (i *MealItem) Recalculate(rep repository.Repository, food *Food) (*MealItem, error) {
+	grams, err := food.grams(i.quantity, i.unit)
+	if err != nil {
+		return nil, err
+	}
+	i.snapshot(food, grams)
+	if err := rep.Model(i).Select("grams", "calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium",
+		"calculated_at").Updates(i).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Where("meal_item_id = ?", i.meal_item_id).Delete(&MealItemNutrient{}).Error; err != nil {
+		return nil, err
+	}
+	if err := i.createNutrients(rep); err != nil {
+		return nil, err
+	}
+	i.calculate(food.food_name, i.per100g(), grams)
+	return i, nil
+}
+
+// Snapshot fills the snapshots of the items which were logged before the nutrients were snapshotted
+// with the current nutrients and sizes of their foods.
+func (i *MealItem) Snapshot(rep repository.Repository) error {
+	if err := rep.Exec(snapshotMealItemNutrients).Error; err != nil {
+		return err
+	}
+	columns := map[string]interface{}{"grams": gorm.Expr(fmt.Sprintf(snapshotMealItemColumn, gramsSQL("meal_items", "f")))}
+	for _, column := range []string{"calo_amount", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium"} {
+		columns[column] = gorm.Expr(fmt.Sprintf(snapshotMealItemColumn, "f."+column))
+	}
+	columns["calculated_at"] = time.Now()
+	return rep.Model(&MealItem{}).Where("calculated_at is null").Updates(columns).Error
+}
+
+// snapshot copies the nutrients per 100 g of given Food and sets given portion in grams.
+func (i *MealItem) snapshot(food *Food, grams float64) {
+	now := time.Now()
+	i.grams = grams
+	i.calculated_at = &now
+	i.setSnapshot(food.nutrients())
+}
+
+func (i *MealItem) setSnapshot(per100g *Nutrients) {
+	i.calo_amount, i.protein, i.carbohydrate, i.fat = per100g.calories, per100g.protein, per100g.carbohydrate, per100g.fat
+	i.fiber, i.sugar, i.sodium = per100g.fiber, per100g.sugar, per100g.sodium
+	i.micronutrients = per100g.micronutrients
+}
+
+// per100g returns the snapshot of the nutrients per 100 g of the food of this MealItem.
+func (i *MealItem) per100g() *Nutrients {
+	return NewNutrients(i.calo_amount, i.protein, i.carbohydrate, i.fat, i.fiber, i.sugar, i.sodium, i.micronutrients)
+}
+
+func (i *MealItem) createNutrients(rep repository.Repository) error {
+	for name, amount := range i.micronutrients {
+		n := MealItemNutrient{meal_item_id: i.meal_item_id, nutrient_name: name, amount: amount}
+		if err := rep.Select("meal_item_id", "nutrient_name", "amount").Create(&n).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveBarcode returns the Food matched the barcode of this item.
+func (i *MealItem) resolveBarcode(rep repository.Repository) (*Food, error) {
+	return findFoodByBarcode(rep, i.barcode)
+}
+
+// findFoodByBarcode returns the Food matched given EAN-13 or UPC-A barcode.
+func findFoodByBarcode(rep repository.Repository, code string) (*Food, error) {
+	barcode, err := util.NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+	food := Food{}
+	f, err := food.FindByBarcode(rep, barcode).Take()
+	if err != nil {
+		return nil, fmt.Errorf("food of barcode %s is not found", barcode)
+	}
+	return f, nil
+}
+
+// calculate computes the calories and the nutrients of this item of given grams from the nutrients per 100 g of its food.
+func (i *MealItem) calculate(food_name string, per100g *Nutrients, grams float64) {
+	i.food_name = food_name
+	i.grams = grams
+	i.nutrients = *portion(per100g, grams)
+	i.calories = i.nutrients.calories
+}
+
+// portion returns the nutrients of given grams of a food from the nutrients per 100 g.
+func portion(per100g *Nutrients, grams float64) *Nutrients {
+	return per100g.scale(grams / 100)
+}
+
+// gramsSQL returns the SQL expression which converts the portion of an item to grams in the same way as util.ToGrams.
+// The item and the food are the aliases of the table of the item and the foods table.
+// The portion which cannot be converted is 0 g because it is rejected when the item is created.
+func gramsSQL(item string, food string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(%s.quantity * case %s.unit", item, item)
+	for _, unit := range util.Units {
+		switch {
+		case unit.Kind == util.UnitKindMass:
+			fmt.Fprintf(&b, " when '%s' then %g", unit.Name, unit.Factor)
+		case unit.Kind == util.UnitKindVolume:
+			fmt.Fprintf(&b, " when '%s' then %g * %s.density", unit.Name, unit.Factor, food)
+		case unit.Name == util.UnitPiece:
+			fmt.Fprintf(&b, " when '%s' then %s.piece_size", unit.Name, food)
+		case unit.Name == util.UnitServing:
+			fmt.Fprintf(&b, " when '%s' then coalesce(nullif(%s.serving_size, 0), %g)", unit.Name, food, util.DefaultServingSize)
+		}
+	}
+	b.WriteString(" else 0 end)")
+	return b.String()
+}
+
+// Error returns the food and the unit which cannot be converted.
+func (e *UnitError) Error() string {
+	return fmt.Sprintf("%s cannot be measured in %s: %s", e.Food, e.Unit, e.Err.Error())
+}
+
+// Unwrap returns util.ErrUnknownUnit or util.ErrIncompatibleUnit.
+func (e *UnitError) Unwrap() error {
+	return e.Err
+}
+
+// ToString is return string of object
+func (i *MealItem) ToString() string {
+	return toString(i)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+func TestMealItemSnapshotIsKeptAfterFoodEdit(t *testing.T) {
+	oats := NewFood("Oats", NewNutrients(389, 16.9, 66.3, 6.9, 10.6, 0, 2, map[string]float64{"iron": 4.7}))
+	oats.serving_size = 40
+
+	item := NewMealItem(0, 1.5, UnitServing)
+	grams, err := oats.grams(item.quantity, item.unit)
+	if err != nil {
+		t.Fatalf("grams() error = %v", err)
+	}
+	item.snapshot(oats, grams)
+	item.calculate(oats.food_name, item.per100g(), grams)
+
+	// The Food is corrected after the item was logged.
+	oats.calo_amount, oats.serving_size = 420, 50
+
+	if item.grams != 60 {
+		t.Errorf("grams = %v, want 60 for 1.5 servings of 40 g", item.grams)
+	}
+	if math.Abs(item.calories-233.4) > 1e-9 {
+		t.Errorf("calories = %v, want 233.4 from the snapshot", item.calories)
+	}
+	if math.Abs(item.nutrients.micronutrients["iron"]-2.82) > 1e-9 {
+		t.Errorf("iron = %v, want 2.82 from the snapshot", item.nutrients.micronutrients["iron"])
+	}
+}
+
+func TestMealItemGramsNeedFoodSizes(t *testing.T) {
+	milk := NewFood("Milk", NewNutrients(64, 3.4, 4.8, 3.6, 0, 4.8, 44, nil))
+
+	_, err := milk.grams(200, "ml")
+	var unitErr *UnitError
+	if !errors.As(err, &unitErr) || !errors.Is(err, util.ErrIncompatibleUnit) {
+		t.Fatalf("grams() error = %v, want a UnitError of util.ErrIncompatibleUnit", err)
+	}
+
+	milk.density = 1.03
+	grams, err := milk.grams(200, "ml")
+	if err != nil || math.Abs(grams-206) > 1e-9 {
+		t.Errorf("grams() = %v, %v, want 206", grams, err)
+	}
+}
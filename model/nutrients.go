@@ -0,0 +1,51 @@
+package model
+
+// Nutrients defines struct of the energy and the nutrients of foods.
+// The amounts of a Food are per 100 g, the others are the amounts actually taken.
+type Nutrients struct {
+	calories       float64            `json:"calories"`
+	protein        float64            `json:"protein"`
+	carbohydrate   float64            `json:"carbohydrate"`
+	fat            float64            `json:"fat"`
+	fiber          float64            `json:"fiber"`
+	sugar          float64            `json:"sugar"`
+	sodium         float64            `json:"sodium"`
+	micronutrients map[string]float64 `json:"micronutrients"`
+}
+
+// NewNutrients is constructor
+func NewNutrients(calories float64, protein float64, carbohydrate float64, fat float64,
+	fiber float64, sugar float64, sodium float64, micronutrients map[string]float64) *Nutrients {
+	if micronutrients == nil {
+		micronutrients = make(map[string]float64)
+	}
+	return &Nutrients{calories: calories, protein: protein, carbohydrate: carbohydrate, fat: fat,
+		fiber: fiber, sugar: sugar, sodium: sodium, micronutrients: micronutrients}
+}
+
+// add adds given nutrients to this nutrients.
+func (n *Nutrients) add(o *Nutrients) {
+	n.calories += o.calories
+	n.protein += o.protein
+	n.carbohydrate += o.carbohydrate
+	n.fat += o.fat
+	n.fiber += o.fiber
+	n.sugar += o.sugar
+	n.sodium += o.sodium
+	if n.micronutrients == nil {
+		n.micronutrients = make(map[string]float64)
+	}
+	for name, amount := range o.micronutrients {
+		n.micronutrients[name] += amount
+	}
+}
+
+// scale returns new nutrients multiplied by given ratio.
+func (n *Nutrients) scale(ratio float64) *Nutrients {
+	micronutrients := make(map[string]float64, len(n.micronutrients))
+	for name, amount := range n.micronutrients {
+		micronutrients[name] = amount * ratio
+	}
+	return NewNutrients(n.calories*ratio, n.protein*ratio, n.carbohydrate*ratio, n.fat*ratio,
+		n.fiber*ratio, n.sugar*ratio, n.sodium*ratio, micronutrients)
+}
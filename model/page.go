@@ -1,14 +1,18 @@
 package model
 
 // Page defines struct of pagination data.
+// Content is a pointer to the slice of the elements such as *[]Meal.
+// Next and Prev are the opaque cursors of the adjacent pages, which are set only when the page is fetched by a cursor.
 type Page struct {
-	Content          *[]Meal `json:"content"`
-	Last             meal    `json:"last"`
-	TotalElements    int     `json:"totalElements"`
-	TotalPages       int     `json:"totalPages"`
-	Size             int     `json:"size"`
-	Page             int     `json:"page"`
-	NumberOfElements int     `json:"numberOfElements"`
+	Content          interface{} `json:"content"`
+	Last             bool        `json:"last"`
+	TotalElements    int         `json:"totalElements"`
+	TotalPages       int         `json:"totalPages"`
+	Size             int         `json:"size"`
+	Page             int         `json:"page"`
+	NumberOfElements int         `json:"numberOfElements"`
+	Next             string      `json:"next,omitempty"`
+	Prev             string      `json:"prev,omitempty"`
 }
 
 // NewPage is constructor
@@ -0,0 +1,327 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	// PlanStatusPlanned represents the PlannedMeal which is not eaten yet.
+	PlanStatusPlanned = "planned"
+	// PlanStatusEaten represents the PlannedMeal which has been eaten and logged as a Meal.
+	PlanStatusEaten = "eaten"
+	// PlanStatusSkipped represents the PlannedMeal which has been skipped.
+	PlanStatusSkipped = "skipped"
+)
+
+// PlannedMeal defines struct of a Meal which a user schedules for a future date.
+// When it is eaten, it is logged as a Meal and meal_id refers to the Meal.
+type PlannedMeal struct {
+	planned_meal_id uint              `gorm:"primary_key" json:"id"`
+	user_id         uint              `json:"user_id"`
+	meal_name       string            `json:"meal_name"`
+	planned_at      time.Time         `json:"planned_at"`
+	status          string            `json:"status"`
+	meal_id         *uint             `json:"meal_id"`
+	items           []PlannedMealItem `gorm:"-" json:"items"`
+	calories        float64           `gorm:"-" json:"calories"`
+	nutrients       Nutrients         `gorm:"-" json:"nutrients"`
+}
+
+// PlannedMealItem defines struct of a food and its portion in a PlannedMeal.
+type PlannedMealItem struct {
+	planned_item_id uint      `gorm:"primary_key" json:"id"`
+	planned_meal_id uint      `json:"planned_meal_id"`
+	food_id         uint      `json:"food_id"`
+	quantity        float64   `json:"quantity"`
+	unit            string    `json:"unit"`
+	barcode         string    `gorm:"-" json:"-"`
+	food_name       string    `gorm:"-" json:"food_name"`
+	grams           float64   `gorm:"-" json:"grams"`
+	calories        float64   `gorm:"-" json:"calories"`
+	nutrients       Nutrients `gorm:"-" json:"nutrients"`
+}
+
+// RecordPlannedMealItem defines struct represents the record of the database.
+type RecordPlannedMealItem struct {
+	planned_item_id uint
+	planned_meal_id uint
+	food_id         uint
+	quantity        float64
+	unit            string
+	food_name       string
+	grams           float64
+	calo_amount     float64
+	protein         float64
+	carbohydrate    float64
+	fat             float64
+	fiber           float64
+	sugar           float64
+	sodium          float64
+}
+
+// MealPlanDay defines struct of the planned and the logged Meals of a day side by side.
+// The planned calories exclude the skipped PlannedMeals, and the delta is the logged calories minus the planned ones.
+type MealPlanDay struct {
+	date             string        `json:"date"`
+	planned          []PlannedMeal `json:"planned"`
+	logged           []Meal        `json:"logged"`
+	planned_calories float64       `json:"planned_calories"`
+	logged_calories  float64       `json:"logged_calories"`
+	calorie_delta    float64       `json:"calorie_delta"`
+}
+
+var selectPlannedMealItem = "select p.planned_item_id as planned_item_id, p.planned_meal_id as planned_meal_id, " +
+	"p.food_id as food_id, p.quantity as quantity, p.unit as unit, f.food_name as food_name, " +
+	gramsSQL("p", "f") + " as grams, f.calo_amount as calo_amount, " +
+	"f.protein as protein, f.carbohydrate as carbohydrate, f.fat as fat, f.fiber as fiber, f.sugar as sugar, f.sodium as sodium " +
+	"from planned_meal_items p inner join foods f on f.food_id = p.food_id " +
+	"where p.planned_meal_id in ? order by p.planned_item_id"
+
+// TableName returns the table name of PlannedMeal struct and it is used by gorm.
+func (PlannedMeal) TableName() string {
+	return "planned_meals"
+}
+
+// TableName returns the table name of PlannedMealItem struct and it is used by gorm.
+func (PlannedMealItem) TableName() string {
+	return "planned_meal_items"
+}
+
+// NewPlannedMeal is constructor. The items are the foods and the portions of the Meal to eat.
+func NewPlannedMeal(user *User, meal_name string, planned_at time.Time, items []MealItem) *PlannedMeal {
+	planned := make([]PlannedMealItem, len(items))
+	for i := range items {
+		planned[i] = PlannedMealItem{food_id: items[i].food_id, barcode: items[i].barcode, quantity: items[i].quantity, unit: items[i].unit}
+	}
+	return &PlannedMeal{user_id: user.user_id, meal_name: meal_name, planned_at: planned_at, status: PlanStatusPlanned, items: planned}
+}
+
+// FindByID returns a PlannedMeal of a given user full matched given ID.
+func (p *PlannedMeal) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*PlannedMeal] {
+	var plan PlannedMeal
+	if err := rep.Where("planned_meal_id = ? and user_id = ?", id, user.user_id).First(&plan).Error; err != nil {
+		return optional.None[*PlannedMeal]()
+	}
+	plans := []PlannedMeal{plan}
+	if err := loadPlannedItems(rep, plans); err != nil {
+		return optional.None[*PlannedMeal]()
+	}
+	return optional.Some(&plans[0])
+}
+
+// FindByUserAndRange returns the PlannedMeals of a given user scheduled in [from, to) in order of time.
+func (p *PlannedMeal) FindByUserAndRange(rep repository.Repository, user *User, from time.Time, to time.Time) ([]PlannedMeal, error) {
+	var plans []PlannedMeal
+	if err := rep.Where("user_id = ? and planned_at >= ? and planned_at < ?", user.user_id, from, to).
+		Order("planned_at").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	if err := loadPlannedItems(rep, plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// loadPlannedItems fetches the items of given PlannedMeals and computes the calories of them.
+func loadPlannedItems(rep repository.Repository, plans []PlannedMeal) error {
+	if len(plans) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(plans))
+	for i := range plans {
+		ids[i] = plans[i].planned_meal_id
+	}
+
+	var recs []RecordPlannedMealItem
+	var rec RecordPlannedMealItem
+	var rows *sql.Rows
+	var err error
+	if rows, err = rep.Raw(selectPlannedMealItem, ids).Rows(); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var food_ids []uint
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return err
+		}
+		recs = append(recs, rec)
+		food_ids = append(food_ids, rec.food_id)
+	}
+
+	nutrient := FoodNutrient{}
+	micronutrients, err := nutrient.FindByFoodIDs(rep, food_ids)
+	if err != nil {
+		return err
+	}
+
+	items := make(map[uint][]PlannedMealItem)
+	for _, rec := range recs {
+		item := PlannedMealItem{planned_item_id: rec.planned_item_id, planned_meal_id: rec.planned_meal_id,
+			food_id: rec.food_id, quantity: rec.quantity, unit: rec.unit}
+		item.calculate(rec.food_name, NewNutrients(rec.calo_amount, rec.protein, rec.carbohydrate, rec.fat,
+			rec.fiber, rec.sugar, rec.sodium, micronutrients[rec.food_id]), rec.grams)
+		items[rec.planned_meal_id] = append(items[rec.planned_meal_id], item)
+	}
+	for i := range plans {
+		plans[i].setItems(items[plans[i].planned_meal_id])
+	}
+	return nil
+}
+
+// Create persists this PlannedMeal data and its items.
+func (p *PlannedMeal) Create(rep repository.Repository) (*PlannedMeal, error) {
+	if err := rep.Select("user_id", "meal_name", "planned_at", "status").Create(p).Error; err != nil {
+		return nil, err
+	}
+
+	food := Food{}
+	for i := range p.items {
+		if p.items[i].food_id == 0 {
+			f, err := findFoodByBarcode(rep, p.items[i].barcode)
+			if err != nil {
+				return nil, err
+			}
+			p.items[i].food_id = f.food_id
+		}
+		f, err := food.FindVisibleByID(rep, &User{user_id: p.user_id}, p.items[i].food_id).Take()
+		if err != nil {
+			return nil, fmt.Errorf("food %d is not found", p.items[i].food_id)
+		}
+		grams, err := f.grams(p.items[i].quantity, p.items[i].unit)
+		if err != nil {
+			return nil, err
+		}
+		p.items[i].planned_meal_id = p.planned_meal_id
+		if err := rep.Select("planned_meal_id", "food_id", "quantity", "unit").Create(&p.items[i]).Error; err != nil {
+			return nil, err
+		}
+		p.items[i].calculate(f.food_name, f.nutrients(), grams)
+	}
+	p.setItems(p.items)
+	return p, nil
+}
+
+// Eat logs this PlannedMeal as a Meal of given user at the planned time and marks it as eaten.
+func (p *PlannedMeal) Eat(rep repository.Repository, user *User) (*Meal, error) {
+	if p.status != PlanStatusPlanned {
+		return nil, fmt.Errorf("the planned meal is already %s", p.status)
+	}
+
+	items := make([]MealItem, len(p.items))
+	for i := range p.items {
+		items[i] = *NewMealItem(p.items[i].food_id, p.items[i].quantity, p.items[i].unit)
+	}
+	meal, err := NewMeal(p.meal_name, user, p.planned_at, items, nil).Create(rep, user)
+	if err != nil {
+		return nil, err
+	}
+
+	p.status = PlanStatusEaten
+	p.meal_id = &meal.meal_id
+	if err := rep.Model(p).Select("status", "meal_id").Updates(p).Error; err != nil {
+		return nil, err
+	}
+	return meal, nil
+}
+
+// Skip marks this PlannedMeal as skipped.
+func (p *PlannedMeal) Skip(rep repository.Repository) (*PlannedMeal, error) {
+	if p.status != PlanStatusPlanned {
+		return nil, fmt.Errorf("the planned meal is already %s", p.status)
+	}
+
+	p.status = PlanStatusSkipped
+	if err := rep.Model(p).Select("status").Updates(p).Error; err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Delete deletes this PlannedMeal data and its items. The Meal logged from it is not changed.
+func (p *PlannedMeal) Delete(rep repository.Repository) (*PlannedMeal, error) {
+	if err := rep.Where("planned_meal_id = ?", p.planned_meal_id).Delete(&PlannedMealItem{}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Delete(p).Error; err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PlannedMeal) setItems(items []PlannedMealItem) {
+	if items == nil {
+		items = []PlannedMealItem{}
+	}
+	p.items = items
+	p.nutrients = *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)
+	for i := range items {
+		p.nutrients.add(&items[i].nutrients)
+	}
+	p.calories = p.nutrients.calories
+}
+
+// calculate computes the calories and the nutrients of this item of given grams from the nutrients per 100 g of its food.
+func (i *PlannedMealItem) calculate(food_name string, per100g *Nutrients, grams float64) {
+	i.food_name = food_name
+	i.grams = grams
+	i.nutrients = *portion(per100g, grams)
+	i.calories = i.nutrients.calories
+}
+
+// FindByUserAndRange returns the planned and the logged Meals of a given user for each day in [from, to].
+func (d *MealPlanDay) FindByUserAndRange(rep repository.Repository, user *User, from time.Time, to time.Time) (*[]MealPlanDay, error) {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return nil, errors.New("from must not be after to")
+	}
+
+	plan := PlannedMeal{}
+	plans, err := plan.FindByUserAndRange(rep, user, start, end)
+	if err != nil {
+		return nil, err
+	}
+	meals, err := findRows(rep, selectMeal+findByUserAndDate, "", "", []interface{}{user.user_id, start.UTC(), end.UTC()})
+	if err != nil {
+		return nil, err
+	}
+
+	var days []MealPlanDay
+	index := make(map[string]int)
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		key := date.Format(util.DateLayout)
+		index[key] = len(days)
+		days = append(days, MealPlanDay{date: key, planned: []PlannedMeal{}, logged: []Meal{}})
+	}
+	for i := range plans {
+		day := &days[index[plans[i].planned_at.In(start.Location()).Format(util.DateLayout)]]
+		day.planned = append(day.planned, plans[i])
+		if plans[i].status != PlanStatusSkipped {
+			day.planned_calories += plans[i].calories
+		}
+	}
+	for i := range meals {
+		day := &days[index[meals[i].meal_at.In(start.Location()).Format(util.DateLayout)]]
+		day.logged = append(day.logged, meals[i])
+		day.logged_calories += meals[i].calories
+	}
+	for i := range days {
+		days[i].calorie_delta = days[i].logged_calories - days[i].planned_calories
+	}
+	return &days, nil
+}
+
+// ToString is return string of object
+func (p *PlannedMeal) ToString() string {
+	return toString(p)
+}
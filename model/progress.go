@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// GoalProgress defines struct of the comparison between the meals of a day and the Goal which applies to the day.
+type GoalProgress struct {
+	date      string    `json:"date"`
+	goal      *Goal     `json:"goal"`
+	target    Nutrients `json:"target"`
+	consumed  Nutrients `json:"consumed"`
+	remaining Nutrients `json:"remaining"`
+	achieved  bool      `json:"achieved"`
+}
+
+// FindByUserAndDate returns the progress of a given user toward the Goal in a given day.
+// If the user has no Goal in the day, target and remaining are zero.
+func (p *GoalProgress) FindByUserAndDate(rep repository.Repository, user *User, date time.Time) (*GoalProgress, error) {
+	summary := DailySummary{}
+	s, err := summary.FindByUserAndDate(rep, user, date)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GoalProgress{date: date.Format(util.DateLayout), consumed: s.nutrients,
+		target: *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil), remaining: *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)}
+
+	goal := Goal{}
+	if g, err := goal.FindActiveByUserAndDate(rep, user, date).Take(); err == nil {
+		result.goal = g
+		result.target = *g.targets()
+		result.remaining = *NewNutrients(
+			g.calories-s.nutrients.calories,
+			result.target.protein-s.nutrients.protein,
+			result.target.carbohydrate-s.nutrients.carbohydrate,
+			result.target.fat-s.nutrients.fat,
+			0, 0, 0, nil)
+		result.achieved = result.remaining.calories >= 0
+	}
+	return result, nil
+}
@@ -0,0 +1,307 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// Recipe defines struct of a dish which a user cooks from Foods.
+// Editing a Recipe adds a new RecipeVersion, so the meals logged from the former versions are not changed.
+type Recipe struct {
+	recipe_id         uint               `gorm:"primary_key" json:"id"`
+	user_id           uint               `json:"user_id"`
+	recipe_name       string             `json:"recipe_name"`
+	version           int                `json:"version"`
+	recipe_version_id uint               `gorm:"-" json:"recipe_version_id"`
+	servings          float64            `gorm:"-" json:"servings"`
+	ingredients       []RecipeIngredient `gorm:"-" json:"ingredients"`
+	nutrients         Nutrients          `gorm:"-" json:"nutrients"`
+	per_serving       Nutrients          `gorm:"-" json:"per_serving"`
+}
+
+// RecipeVersion defines struct of an immutable version of a Recipe.
+type RecipeVersion struct {
+	recipe_version_id uint      `gorm:"primary_key" json:"id"`
+	recipe_id         uint      `json:"recipe_id"`
+	version           int       `json:"version"`
+	servings          float64   `json:"servings"`
+	created_at        time.Time `json:"created_at"`
+}
+
+// RecipeIngredient defines struct of a Food and its quantity in a RecipeVersion.
+type RecipeIngredient struct {
+	recipe_ingredient_id uint      `gorm:"primary_key" json:"id"`
+	recipe_version_id    uint      `json:"recipe_version_id"`
+	food_id              uint      `json:"food_id"`
+	quantity             float64   `json:"quantity"`
+	unit                 string    `json:"unit"`
+	food_name            string    `gorm:"-" json:"food_name"`
+	grams                float64   `gorm:"-" json:"grams"`
+	nutrients            Nutrients `gorm:"-" json:"nutrients"`
+}
+
+// RecordRecipeIngredient defines struct represents the record of the database.
+type RecordRecipeIngredient struct {
+	recipe_ingredient_id uint
+	recipe_version_id    uint
+	food_id              uint
+	quantity             float64
+	unit                 string
+	food_name            string
+	grams                float64
+	calo_amount          float64
+	protein              float64
+	carbohydrate         float64
+	fat                  float64
+	fiber                float64
+	sugar                float64
+	sodium               float64
+}
+
+// ErrRecipeConflict is returned when a Recipe is updated concurrently and the other update has won.
+var ErrRecipeConflict = errors.New("the recipe has been updated by another request")
+
+var selectRecipeIngredient = "select r.recipe_ingredient_id as recipe_ingredient_id, r.recipe_version_id as recipe_version_id, " +
+	"r.food_id as food_id, r.quantity as quantity, r.unit as unit, f.food_name as food_name, " +
+	gramsSQL("r", "f") + " as grams, f.calo_amount as calo_amount, " +
+	"f.protein as protein, f.carbohydrate as carbohydrate, f.fat as fat, f.fiber as fiber, f.sugar as sugar, f.sodium as sodium " +
+	"from recipe_ingredients r inner join foods f on f.food_id = r.food_id where r.recipe_version_id = ? order by r.recipe_ingredient_id"
+
+// selectDuplicateRecipeVersion selects the versions which have the same number as an earlier version of the recipe.
+var selectDuplicateRecipeVersion = "select v.recipe_version_id, v.recipe_id from recipe_versions v where exists " +
+	"(select 1 from recipe_versions w where w.recipe_id = v.recipe_id and w.version = v.version " +
+	"and w.recipe_version_id < v.recipe_version_id) order by v.recipe_version_id"
+
+// TableName returns the table name of Recipe struct and it is used by gorm.
+func (Recipe) TableName() string {
+	return "recipes"
+}
+
+// TableName returns the table name of RecipeVersion struct and it is used by gorm.
+func (RecipeVersion) TableName() string {
+	return "recipe_versions"
+}
+
+// TableName returns the table name of RecipeIngredient struct and it is used by gorm.
+func (RecipeIngredient) TableName() string {
+	return "recipe_ingredients"
+}
+
+// NewRecipe is constructor
+func NewRecipe(user *User, recipe_name string, servings float64, ingredients []RecipeIngredient) *Recipe {
+	return &Recipe{user_id: user.user_id, recipe_name: recipe_name, servings: servings, ingredients: ingredients}
+}
+
+// NewRecipeIngredient is constructor
+func NewRecipeIngredient(food_id uint, quantity float64, unit string) *RecipeIngredient {
+	return &RecipeIngredient{food_id: food_id, quantity: quantity, unit: unit}
+}
+
+// FindByID returns a Recipe of a given user full matched given ID.
+// If version is 0, it returns the current version.
+func (r *Recipe) FindByID(rep repository.Repository, user *User, id uint, version int) optional.Option[*Recipe] {
+	var recipe Recipe
+	if err := rep.Where("recipe_id = ? and user_id = ?", id, user.user_id).First(&recipe).Error; err != nil {
+		return optional.None[*Recipe]()
+	}
+	if version == 0 {
+		version = recipe.version
+	}
+	if err := recipe.load(rep, version); err != nil {
+		return optional.None[*Recipe]()
+	}
+	return optional.Some(&recipe)
+}
+
+// FindByUser returns the current versions of all Recipes of a given user.
+func (r *Recipe) FindByUser(rep repository.Repository, user *User) (*[]Recipe, error) {
+	var recipes []Recipe
+	if err := rep.Where("user_id = ?", user.user_id).Order("recipe_name").Find(&recipes).Error; err != nil {
+		return nil, err
+	}
+	for i := range recipes {
+		if err := recipes[i].load(rep, recipes[i].version); err != nil {
+			return nil, err
+		}
+	}
+	return &recipes, nil
+}
+
+// Create persists this Recipe data as the first version.
+func (r *Recipe) Create(rep repository.Repository) (*Recipe, error) {
+	r.version = 1
+	if err := rep.Select("user_id", "recipe_name", "version").Create(r).Error; err != nil {
+		return nil, err
+	}
+	if err := r.createVersion(rep); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Update adds a new version of this Recipe by given Recipe. The former versions are kept.
+// It returns ErrRecipeConflict if another update has added a version since this Recipe was loaded.
+func (r *Recipe) Update(rep repository.Repository, recipe *Recipe) (*Recipe, error) {
+	current := r.version
+	r.recipe_name = recipe.recipe_name
+	r.servings = recipe.servings
+	r.ingredients = recipe.ingredients
+	r.version++
+	result := rep.Model(r).Where("version = ?", current).Select("recipe_name", "version").Updates(r)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrRecipeConflict
+	}
+	if err := r.createVersion(rep); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Scale returns a copy of this Recipe which quantities and nutrients are scaled to given servings.
+func (r *Recipe) Scale(servings float64) *Recipe {
+	ratio := servings / r.servings
+	scaled := *r
+	scaled.servings = servings
+	scaled.nutrients = *r.nutrients.scale(ratio)
+	scaled.ingredients = make([]RecipeIngredient, len(r.ingredients))
+	for i, ingredient := range r.ingredients {
+		ingredient.quantity *= ratio
+		ingredient.grams *= ratio
+		ingredient.nutrients = *ingredient.nutrients.scale(ratio)
+		scaled.ingredients[i] = ingredient
+	}
+	return &scaled
+}
+
+// mealItems returns the items of a Meal which has taken given servings of this Recipe.
+func (r *Recipe) mealItems(servings float64) []MealItem {
+	scaled := r.Scale(servings)
+	items := make([]MealItem, len(scaled.ingredients))
+	for i, ingredient := range scaled.ingredients {
+		recipe_version_id := r.recipe_version_id
+		items[i] = *NewMealItem(ingredient.food_id, ingredient.quantity, ingredient.unit)
+		items[i].recipe_version_id = &recipe_version_id
+	}
+	return items
+}
+
+func (r *Recipe) createVersion(rep repository.Repository) error {
+	version := RecipeVersion{recipe_id: r.recipe_id, version: r.version, servings: r.servings, created_at: time.Now()}
+	if err := rep.Select("recipe_id", "version", "servings", "created_at").Create(&version).Error; err != nil {
+		return err
+	}
+	r.recipe_version_id = version.recipe_version_id
+
+	food := Food{}
+	for i := range r.ingredients {
+		f, err := food.FindVisibleByID(rep, &User{user_id: r.user_id}, r.ingredients[i].food_id).Take()
+		if err != nil {
+			return errors.New("food is not found")
+		}
+		if _, err := f.grams(r.ingredients[i].quantity, r.ingredients[i].unit); err != nil {
+			return err
+		}
+		r.ingredients[i].recipe_ingredient_id = 0
+		r.ingredients[i].recipe_version_id = version.recipe_version_id
+		if err := rep.Select("recipe_version_id", "food_id", "quantity", "unit").Create(&r.ingredients[i]).Error; err != nil {
+			return err
+		}
+	}
+	return r.load(rep, r.version)
+}
+
+// load fetches given version of this Recipe and computes its nutrients.
+func (r *Recipe) load(rep repository.Repository, version int) error {
+	var v RecipeVersion
+	if err := rep.Where("recipe_id = ? and version = ?", r.recipe_id, version).First(&v).Error; err != nil {
+		return err
+	}
+	r.version = v.version
+	r.recipe_version_id = v.recipe_version_id
+	r.servings = v.servings
+
+	var recs []RecordRecipeIngredient
+	var rec RecordRecipeIngredient
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(selectRecipeIngredient, v.recipe_version_id).Rows(); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var food_ids []uint
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return err
+		}
+		recs = append(recs, rec)
+		food_ids = append(food_ids, rec.food_id)
+	}
+
+	nutrient := FoodNutrient{}
+	micronutrients, err := nutrient.FindByFoodIDs(rep, food_ids)
+	if err != nil {
+		return err
+	}
+
+	r.ingredients = []RecipeIngredient{}
+	r.nutrients = *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil)
+	for _, rec := range recs {
+		ingredient := RecipeIngredient{recipe_ingredient_id: rec.recipe_ingredient_id, recipe_version_id: rec.recipe_version_id,
+			food_id: rec.food_id, quantity: rec.quantity, unit: rec.unit, food_name: rec.food_name, grams: rec.grams}
+		ingredient.nutrients = *portion(NewNutrients(rec.calo_amount, rec.protein, rec.carbohydrate, rec.fat,
+			rec.fiber, rec.sugar, rec.sodium, micronutrients[rec.food_id]), rec.grams)
+		r.nutrients.add(&ingredient.nutrients)
+		r.ingredients = append(r.ingredients, ingredient)
+	}
+	r.per_serving = *r.nutrients.scale(1 / r.servings)
+	return nil
+}
+
+// RenumberDuplicateVersions renumbers the versions which were written with the same number by the concurrent updates
+// of a recipe before the number was unique. The later one of them gets the next number of the recipe and becomes current,
+// as the last write. The rows are kept, because the meals logged from either of them refer to it.
+func (r *Recipe) RenumberDuplicateVersions(rep repository.Repository) error {
+	rows, err := rep.Raw(selectDuplicateRecipeVersion).Rows()
+	if err != nil {
+		return err
+	}
+	var ids, recipe_ids []uint
+	for rows.Next() {
+		var id, recipe_id uint
+		if err := rows.Scan(&id, &recipe_id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+		recipe_ids = append(recipe_ids, recipe_id)
+	}
+	rows.Close()
+
+	for i := range ids {
+		var version int
+		if err := rep.Raw("select max(version) from recipe_versions where recipe_id = ?", recipe_ids[i]).Row().Scan(&version); err != nil {
+			return err
+		}
+		if err := rep.Exec("update recipe_versions set version = ? where recipe_version_id = ?", version+1, ids[i]).Error; err != nil {
+			return err
+		}
+		if err := rep.Exec("update recipes set version = ? where recipe_id = ?", version+1, recipe_ids[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToString is return string of object
+func (r *Recipe) ToString() string {
+	return toString(r)
+}
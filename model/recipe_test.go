@@ -0,0 +1,74 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqlRepository runs the raw SQL of a Repository on an in-memory SQLite database.
+type sqlRepository struct {
+	repository.Repository
+	db *gorm.DB
+}
+
+func (rep *sqlRepository) Raw(sql string, values ...interface{}) *gorm.DB {
+	return rep.db.Raw(sql, values...)
+}
+
+func (rep *sqlRepository) Exec(sql string, values ...interface{}) *gorm.DB {
+	return rep.db.Exec(sql, values...)
+}
+
+func TestRenumberDuplicateRecipeVersions(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	rep := &sqlRepository{db: db}
+	for _, sql := range []string{
+		"create table recipes (recipe_id integer primary key, version integer)",
+		"create table recipe_versions (recipe_version_id integer primary key, recipe_id integer, version integer)",
+		// Two updates of recipe 1 have both written the version 2, and three of recipe 2 the version 1.
+		"insert into recipes (recipe_id, version) values (1, 2), (2, 1), (3, 1)",
+		"insert into recipe_versions (recipe_version_id, recipe_id, version) values " +
+			"(10, 1, 1), (11, 1, 2), (12, 2, 1), (13, 1, 2), (14, 2, 1), (15, 3, 1), (16, 2, 1)",
+	} {
+		if err := rep.Exec(sql).Error; err != nil {
+			t.Fatalf("%s: error = %v", sql, err)
+		}
+	}
+
+	recipe := Recipe{}
+	if err := recipe.RenumberDuplicateVersions(rep); err != nil {
+		t.Fatalf("RenumberDuplicateVersions() error = %v", err)
+	}
+
+	var versions []struct {
+		RecipeVersionID uint
+		Version         int
+	}
+	if err := rep.Raw("select recipe_version_id, version from recipe_versions order by recipe_version_id").Scan(&versions).Error; err != nil {
+		t.Fatalf("select error = %v", err)
+	}
+	want := map[uint]int{10: 1, 11: 2, 12: 1, 13: 3, 14: 2, 15: 1, 16: 3}
+	for _, v := range versions {
+		if v.Version != want[v.RecipeVersionID] {
+			t.Errorf("version of %d = %d, want %d", v.RecipeVersionID, v.Version, want[v.RecipeVersionID])
+		}
+	}
+
+	// The later duplicate is current, and the next update of the recipe continues from it.
+	var current []int
+	if err := rep.Raw("select version from recipes order by recipe_id").Scan(&current).Error; err != nil {
+		t.Fatalf("select error = %v", err)
+	}
+	if len(current) != 3 || current[0] != 3 || current[1] != 3 || current[2] != 1 {
+		t.Errorf("current versions = %v, want [3 3 1]", current)
+	}
+	if err := rep.Exec("create unique index idx_recipe_versions_version on recipe_versions (recipe_id, version)").Error; err != nil {
+		t.Errorf("create unique index error = %v, want the duplicates to be gone", err)
+	}
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+const (
+	// RestrictionModeWarn represents that a Meal which conflicts with the restrictions is logged with the warnings.
+	RestrictionModeWarn = "warn"
+	// RestrictionModeReject represents that a Meal which conflicts with the restrictions is rejected.
+	RestrictionModeReject = "reject"
+)
+
+// UserRestriction defines struct of an allergen which a user avoids or a diet which a user follows.
+type UserRestriction struct {
+	user_restriction_id uint   `gorm:"primary_key" json:"id"`
+	user_id             uint   `json:"user_id"`
+	kind                string `json:"kind"`
+	tag                 string `json:"tag"`
+}
+
+// Restrictions defines struct of the allergens which a user avoids and the diets which a user follows,
+// and whether the Meals which conflict with them are warned or rejected.
+type Restrictions struct {
+	mode      string   `json:"mode"`
+	allergens []string `json:"allergens"`
+	diets     []string `json:"diets"`
+}
+
+// RestrictionError is returned when a Meal conflicts with the restrictions of the user who rejects such Meals.
+type RestrictionError struct {
+	Conflicts []string
+}
+
+// TableName returns the table name of UserRestriction struct and it is used by gorm.
+func (UserRestriction) TableName() string {
+	return "user_restrictions"
+}
+
+// Error returns the conflicts joined into a message.
+func (e *RestrictionError) Error() string {
+	return "the meal conflicts with the restrictions: " + strings.Join(e.Conflicts, ", ")
+}
+
+// LoadRestrictions fetches the restriction mode, the allergens and the diets of this User.
+func (u *User) LoadRestrictions(rep repository.Repository) error {
+	var user User
+	if err := rep.Where("user_id = ?", u.user_id).First(&user).Error; err != nil {
+		return err
+	}
+	u.restriction_mode = user.restriction_mode
+
+	var restrictions []UserRestriction
+	if err := rep.Where("user_id = ?", u.user_id).Order("tag").Find(&restrictions).Error; err != nil {
+		return err
+	}
+	u.allergens, u.diets = []string{}, []string{}
+	for i := range restrictions {
+		switch restrictions[i].kind {
+		case TagAllergen:
+			u.allergens = append(u.allergens, restrictions[i].tag)
+		case TagDiet:
+			u.diets = append(u.diets, restrictions[i].tag)
+		}
+	}
+	if u.restriction_mode == "" {
+		u.restriction_mode = RestrictionModeWarn
+	}
+	return nil
+}
+
+// Restrictions returns the restrictions of this User which are loaded by LoadRestrictions.
+func (u *User) Restrictions() *Restrictions {
+	return &Restrictions{mode: u.restriction_mode, allergens: u.allergens, diets: u.diets}
+}
+
+// UpdateRestrictions replaces the allergens to avoid and the diets to follow of this User,
+// and sets whether a conflicting Meal is warned or rejected.
+func (u *User) UpdateRestrictions(rep repository.Repository, allergens []string, diets []string, mode string) (*User, error) {
+	if err := rep.Where("user_id = ?", u.user_id).Delete(&UserRestriction{}).Error; err != nil {
+		return nil, err
+	}
+	for _, allergen := range allergens {
+		if err := rep.Select("user_id", "kind", "tag").Create(&UserRestriction{user_id: u.user_id, kind: TagAllergen, tag: allergen}).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, diet := range diets {
+		if err := rep.Select("user_id", "kind", "tag").Create(&UserRestriction{user_id: u.user_id, kind: TagDiet, tag: diet}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	u.restriction_mode = mode
+	if err := rep.Model(u).Select("restriction_mode").Updates(u).Error; err != nil {
+		return nil, err
+	}
+	return u, u.LoadRestrictions(rep)
+}
+
+// conflicts returns the reasons why given Food conflicts with the restrictions of this User.
+// A Food conflicts if it contains an allergen to avoid or it is not tagged with a diet to follow.
+func (u *User) conflicts(food *Food) []string {
+	var result []string
+	for _, allergen := range u.allergens {
+		if food.hasTag(TagAllergen, allergen) {
+			result = append(result, fmt.Sprintf("%s contains %s", food.food_name, allergen))
+		}
+	}
+	for _, diet := range u.diets {
+		if !food.hasTag(TagDiet, diet) {
+			result = append(result, fmt.Sprintf("%s is not %s", food.food_name, diet))
+		}
+	}
+	return result
+}
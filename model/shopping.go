@@ -0,0 +1,245 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// defaultCategory is the category of the Foods which have no category.
+const defaultCategory = "Other"
+
+// ShoppingList defines struct of the foods to buy for the planned Meals in a date range and the Recipes to cook.
+type ShoppingList struct {
+	list_id    uint               `gorm:"primary_key" json:"id"`
+	user_id    uint               `json:"user_id"`
+	list_name  string             `json:"list_name"`
+	from_date  time.Time          `json:"from_date"`
+	to_date    time.Time          `json:"to_date"`
+	created_at time.Time          `json:"created_at"`
+	items      []ShoppingListItem `gorm:"-" json:"items"`
+}
+
+// ShoppingListItem defines struct of a food to buy. The quantities of the same food are summed in grams,
+// and converted to the purchase unit of the food such as a bottle, rounding up, if it has one.
+type ShoppingListItem struct {
+	item_id   uint    `gorm:"primary_key" json:"id"`
+	list_id   uint    `json:"list_id"`
+	food_id   uint    `json:"food_id"`
+	food_name string  `json:"food_name"`
+	category  string  `json:"category"`
+	grams     float64 `json:"grams"`
+	quantity  float64 `json:"quantity"`
+	unit      string  `json:"unit"`
+	checked   bool    `json:"checked"`
+}
+
+// TableName returns the table name of ShoppingList struct and it is used by gorm.
+func (ShoppingList) TableName() string {
+	return "shopping_lists"
+}
+
+// TableName returns the table name of ShoppingListItem struct and it is used by gorm.
+func (ShoppingListItem) TableName() string {
+	return "shopping_list_items"
+}
+
+// NewShoppingList is constructor
+func NewShoppingList(user *User, list_name string, from_date time.Time, to_date time.Time) *ShoppingList {
+	return &ShoppingList{user_id: user.user_id, list_name: list_name, from_date: from_date, to_date: to_date, items: []ShoppingListItem{}}
+}
+
+// FindByID returns a ShoppingList of a given user full matched given ID.
+func (l *ShoppingList) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*ShoppingList] {
+	var list ShoppingList
+	if err := rep.Where("list_id = ? and user_id = ?", id, user.user_id).First(&list).Error; err != nil {
+		return optional.None[*ShoppingList]()
+	}
+	if err := rep.Where("list_id = ?", id).Order("category, food_name").Find(&list.items).Error; err != nil {
+		return optional.None[*ShoppingList]()
+	}
+	return optional.Some(&list)
+}
+
+// FindByUser returns the ShoppingLists of a given user without the items, the newest first.
+func (l *ShoppingList) FindByUser(rep repository.Repository, user *User) (*[]ShoppingList, error) {
+	var lists []ShoppingList
+	if err := rep.Where("user_id = ?", user.user_id).Order("created_at desc").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		lists[i].items = []ShoppingListItem{}
+	}
+	return &lists, nil
+}
+
+// Create generates the items from the planned Meals of the user in the date range which are not eaten nor skipped
+// and from given Recipes of given servings, and persists this ShoppingList data with them.
+func (l *ShoppingList) Create(rep repository.Repository, recipes []MealRecipe) (*ShoppingList, error) {
+	user := &User{user_id: l.user_id}
+	from := time.Date(l.from_date.Year(), l.from_date.Month(), l.from_date.Day(), 0, 0, 0, 0, l.from_date.Location())
+	to := time.Date(l.to_date.Year(), l.to_date.Month(), l.to_date.Day(), 0, 0, 0, 0, l.to_date.Location()).AddDate(0, 0, 1)
+
+	plan := PlannedMeal{}
+	plans, err := plan.FindByUserAndRange(rep, user, from, to)
+	if err != nil {
+		return nil, err
+	}
+	portions := make(map[uint][]MealItem)
+	var food_ids []uint
+	add := func(food_id uint, quantity float64, unit string) {
+		if _, ok := portions[food_id]; !ok {
+			food_ids = append(food_ids, food_id)
+		}
+		portions[food_id] = append(portions[food_id], *NewMealItem(food_id, quantity, unit))
+	}
+	for _, p := range plans {
+		if p.status != PlanStatusPlanned {
+			continue
+		}
+		for _, item := range p.items {
+			add(item.food_id, item.quantity, item.unit)
+		}
+	}
+
+	recipe := Recipe{}
+	for _, mr := range recipes {
+		r, err := recipe.FindByID(rep, user, mr.recipe_id, 0).Take()
+		if err != nil {
+			return nil, fmt.Errorf("recipe %d is not found", mr.recipe_id)
+		}
+		for _, item := range r.mealItems(mr.servings) {
+			add(item.food_id, item.quantity, item.unit)
+		}
+	}
+
+	food := Food{}
+	foods, err := food.FindByIDs(rep, food_ids)
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[uint]float64)
+	for i := range foods {
+		for _, p := range portions[foods[i].food_id] {
+			grams, err := foods[i].grams(p.quantity, p.unit)
+			if err != nil {
+				return nil, err
+			}
+			totals[foods[i].food_id] += grams
+		}
+	}
+
+	l.created_at = time.Now()
+	if err := rep.Select("user_id", "list_name", "from_date", "to_date", "created_at").Create(l).Error; err != nil {
+		return nil, err
+	}
+	l.items = []ShoppingListItem{}
+	for i := range foods {
+		item := newShoppingListItem(l.list_id, &foods[i], totals[foods[i].food_id])
+		if err := rep.Create(item).Error; err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, *item)
+	}
+	sort.SliceStable(l.items, func(i, j int) bool {
+		if l.items[i].category != l.items[j].category {
+			return l.items[i].category < l.items[j].category
+		}
+		return l.items[i].food_name < l.items[j].food_name
+	})
+	return l, nil
+}
+
+// newShoppingListItem returns the item to buy given grams of a given Food.
+func newShoppingListItem(list_id uint, food *Food, total float64) *ShoppingListItem {
+	item := &ShoppingListItem{list_id: list_id, food_id: food.food_id, food_name: food.food_name,
+		category: food.category, grams: math.Round(total*10) / 10, quantity: math.Ceil(total), unit: UnitGram}
+	if item.category == "" {
+		item.category = defaultCategory
+	}
+	if food.purchase_unit != "" && food.purchase_size > 0 {
+		item.quantity = math.Ceil(total / food.purchase_size)
+		item.unit = food.purchase_unit
+	}
+	return item
+}
+
+// Check marks or unmarks the item of given ID in this ShoppingList as bought.
+func (l *ShoppingList) Check(rep repository.Repository, item_id uint, checked bool) (*ShoppingListItem, error) {
+	for i := range l.items {
+		if l.items[i].item_id != item_id {
+			continue
+		}
+		l.items[i].checked = checked
+		if err := rep.Model(&l.items[i]).Select("checked").Updates(&l.items[i]).Error; err != nil {
+			return nil, err
+		}
+		return &l.items[i], nil
+	}
+	return nil, fmt.Errorf("item %d is not found", item_id)
+}
+
+// Delete deletes this ShoppingList data and its items.
+func (l *ShoppingList) Delete(rep repository.Repository) (*ShoppingList, error) {
+	if err := rep.Where("list_id = ?", l.list_id).Delete(&ShoppingListItem{}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Delete(l).Error; err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Text returns this ShoppingList as plain text grouped by category with a check box for each item.
+func (l *ShoppingList) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s - %s)\n", l.list_name, l.from_date.Format(util.DateLayout), l.to_date.Format(util.DateLayout))
+	category := ""
+	for i, item := range l.items {
+		if i == 0 || item.category != category {
+			category = item.category
+			fmt.Fprintf(&b, "\n%s\n", category)
+		}
+		mark := " "
+		if item.checked {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "[%s] %s %s %s\n", mark, item.food_name, formatQuantity(item.quantity), item.unit)
+	}
+	return b.String()
+}
+
+// CSV returns this ShoppingList as CSV which has a header row.
+func (l *ShoppingList) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write([]string{"category", "food", "quantity", "unit", "grams", "checked"}); err != nil {
+		return nil, err
+	}
+	for _, item := range l.items {
+		if err := writer.Write([]string{item.category, item.food_name, formatQuantity(item.quantity), item.unit,
+			formatQuantity(item.grams), strconv.FormatBool(item.checked)}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
+
+// ToString is return string of object
+func (l *ShoppingList) ToString() string {
+	return toString(l)
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// DailySummary defines struct of the calories and the nutrients which a user has taken in a day.
+// The calories of the beverages which are also Foods are included in the calories taken.
+// The net calories are the calories taken minus the calories burned by the activities.
+// The energy balance is TDEE minus the calories taken, which is null if TDEE can not be estimated.
+type DailySummary struct {
+	date            string     `json:"date"`
+	total_calories  float64    `json:"total_calories"`
+	nutrients       Nutrients  `json:"nutrients"`
+	meal_count      int        `json:"meal_count"`
+	meals           []Meal     `json:"meals"`
+	hydration       *Hydration `json:"hydration"`
+	burned_calories float64    `json:"burned_calories"`
+	net_calories    float64    `json:"net_calories"`
+	tdee            *float64   `json:"tdee"`
+	energy_balance  *float64   `json:"energy_balance"`
+}
+
+// NewDailySummary is constructor
+func NewDailySummary(date time.Time) *DailySummary {
+	return &DailySummary{date: date.Format(util.DateLayout), nutrients: *NewNutrients(0, 0, 0, 0, 0, 0, 0, nil), meals: []Meal{}}
+}
+
+// FindByUserAndDate returns the summary of the meals which a given user has taken in a given day.
+func (s *DailySummary) FindByUserAndDate(rep repository.Repository, user *User, date time.Time) (*DailySummary, error) {
+	var Meals []Meal
+	var err error
+
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	args := []interface{}{user.user_id, from.UTC(), from.AddDate(0, 0, 1).UTC()}
+
+	if Meals, err = findRows(rep, selectMeal+findByUserAndDate, "", "", args); err != nil {
+		return nil, err
+	}
+
+	summary := NewDailySummary(from)
+	for i := range Meals {
+		summary.add(&Meals[i])
+	}
+
+	hydration := Hydration{}
+	if summary.hydration, err = hydration.FindByUserAndDate(rep, user, from); err != nil {
+		return nil, err
+	}
+	summary.nutrients.add(&summary.hydration.nutrients)
+	summary.total_calories = summary.nutrients.calories
+
+	activity := Activity{}
+	if summary.burned_calories, err = activity.SumCaloriesByUserAndDate(rep, user, from); err != nil {
+		return nil, err
+	}
+	summary.net_calories = summary.total_calories - summary.burned_calories
+
+	energy := Energy{}
+	if e, err := energy.FindByUserAndDate(rep, user, from).Take(); err == nil {
+		balance := e.tdee - summary.total_calories
+		summary.tdee = &e.tdee
+		summary.energy_balance = &balance
+	}
+	return summary, nil
+}
+
+func (s *DailySummary) add(meal *Meal) {
+	s.meals = append(s.meals, *meal)
+	s.meal_count = len(s.meals)
+	s.nutrients.add(&meal.nutrients)
+	s.total_calories = s.nutrients.calories
+}
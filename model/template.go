@@ -0,0 +1,205 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// MealTemplate defines struct of the named set of Meals which a user can log again with one call.
+// It is saved from a Meal or from all Meals of a day. A favorite MealTemplate comes first in the list.
+type MealTemplate struct {
+	template_id   uint               `gorm:"primary_key" json:"id"`
+	user_id       uint               `json:"user_id"`
+	template_name string             `json:"template_name"`
+	favorite      bool               `json:"favorite"`
+	items         []MealTemplateItem `gorm:"-" json:"items"`
+}
+
+// MealTemplateItem defines struct of a food and its portion in a MealTemplate.
+// The Meal which the item belongs to is identified by its name and the time of day in minutes.
+type MealTemplateItem struct {
+	template_item_id  uint    `gorm:"primary_key" json:"id"`
+	template_id       uint    `json:"template_id"`
+	meal_name         string  `json:"meal_name"`
+	time_of_day       int     `json:"time_of_day"`
+	food_id           uint    `json:"food_id"`
+	quantity          float64 `json:"quantity"`
+	unit              string  `json:"unit"`
+	recipe_version_id *uint   `json:"recipe_version_id"`
+	food_name         string  `gorm:"-" json:"food_name"`
+}
+
+// RecordMealTemplateItem defines struct represents the record of the database.
+type RecordMealTemplateItem struct {
+	template_item_id  uint
+	template_id       uint
+	meal_name         string
+	time_of_day       int
+	food_id           uint
+	quantity          float64
+	unit              string
+	recipe_version_id *uint
+	food_name         string
+}
+
+const (
+	selectMealTemplateItem = "select t.template_item_id as template_item_id, t.template_id as template_id, " +
+		"t.meal_name as meal_name, t.time_of_day as time_of_day, t.food_id as food_id, t.quantity as quantity, " +
+		"t.unit as unit, t.recipe_version_id as recipe_version_id, f.food_name as food_name " +
+		"from meal_template_items t inner join foods f on f.food_id = t.food_id"
+	findTemplateItemsByTemplateIDs = " where t.template_id in ? order by t.template_item_id"
+)
+
+// TableName returns the table name of MealTemplate struct and it is used by gorm.
+func (MealTemplate) TableName() string {
+	return "meal_templates"
+}
+
+// TableName returns the table name of MealTemplateItem struct and it is used by gorm.
+func (MealTemplateItem) TableName() string {
+	return "meal_template_items"
+}
+
+// NewMealTemplate is constructor
+func NewMealTemplate(user *User, template_name string, favorite bool) *MealTemplate {
+	return &MealTemplate{user_id: user.user_id, template_name: template_name, favorite: favorite, items: []MealTemplateItem{}}
+}
+
+// FindByID returns a MealTemplate of a given user full matched given ID.
+func (t *MealTemplate) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*MealTemplate] {
+	var template MealTemplate
+	if err := rep.Where("template_id = ? and user_id = ?", id, user.user_id).First(&template).Error; err != nil {
+		return optional.None[*MealTemplate]()
+	}
+	templates := []MealTemplate{template}
+	if err := loadTemplateItems(rep, templates); err != nil {
+		return optional.None[*MealTemplate]()
+	}
+	return optional.Some(&templates[0])
+}
+
+// FindByUser returns the MealTemplates of a given user, the favorites first and then by name.
+func (t *MealTemplate) FindByUser(rep repository.Repository, user *User) (*[]MealTemplate, error) {
+	var templates []MealTemplate
+	if err := rep.Where("user_id = ?", user.user_id).Order("favorite desc, template_name").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	if err := loadTemplateItems(rep, templates); err != nil {
+		return nil, err
+	}
+	return &templates, nil
+}
+
+// loadTemplateItems fetches the items of given MealTemplates.
+func loadTemplateItems(rep repository.Repository, templates []MealTemplate) error {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(templates))
+	for i := range templates {
+		ids[i] = templates[i].template_id
+	}
+
+	var rec RecordMealTemplateItem
+	var rows *sql.Rows
+	var err error
+	if rows, err = rep.Raw(selectMealTemplateItem+findTemplateItemsByTemplateIDs, ids).Rows(); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	items := make(map[uint][]MealTemplateItem)
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return err
+		}
+		items[rec.template_id] = append(items[rec.template_id], MealTemplateItem{template_item_id: rec.template_item_id,
+			template_id: rec.template_id, meal_name: rec.meal_name, time_of_day: rec.time_of_day, food_id: rec.food_id,
+			quantity: rec.quantity, unit: rec.unit, recipe_version_id: rec.recipe_version_id, food_name: rec.food_name})
+	}
+	for i := range templates {
+		templates[i].items = items[templates[i].template_id]
+		if templates[i].items == nil {
+			templates[i].items = []MealTemplateItem{}
+		}
+	}
+	return nil
+}
+
+// Create persists this MealTemplate data with the items of given Meals.
+// The time of day of the Meals is taken in the time zone of the user.
+func (t *MealTemplate) Create(rep repository.Repository, meals []Meal) (*MealTemplate, error) {
+	if err := rep.Select("user_id", "template_name", "favorite").Create(t).Error; err != nil {
+		return nil, err
+	}
+
+	user := &User{user_id: t.user_id}
+	loc := user.Location(rep)
+	t.items = []MealTemplateItem{}
+	for i := range meals {
+		at := meals[i].meal_at.In(loc)
+		minutes := at.Hour()*60 + at.Minute()
+		for _, mi := range meals[i].items {
+			item := MealTemplateItem{template_id: t.template_id, meal_name: meals[i].meal_name, time_of_day: minutes,
+				food_id: mi.food_id, quantity: mi.quantity, unit: mi.unit, recipe_version_id: mi.recipe_version_id, food_name: mi.food_name}
+			if err := rep.Select("template_id", "meal_name", "time_of_day", "food_id", "quantity", "unit", "recipe_version_id").
+				Create(&item).Error; err != nil {
+				return nil, err
+			}
+			t.items = append(t.items, item)
+		}
+	}
+	return t, nil
+}
+
+// SetFavorite marks or unmarks this MealTemplate as a favorite.
+func (t *MealTemplate) SetFavorite(rep repository.Repository, favorite bool) (*MealTemplate, error) {
+	t.favorite = favorite
+	if err := rep.Model(t).Select("favorite").Updates(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Delete deletes this MealTemplate data and its items.
+func (t *MealTemplate) Delete(rep repository.Repository) (*MealTemplate, error) {
+	if err := rep.Where("template_id = ?", t.template_id).Delete(&MealTemplateItem{}).Error; err != nil {
+		return nil, err
+	}
+	if err := rep.Delete(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Meals returns the new Meals of this MealTemplate on a given day, which are not persisted yet.
+// The items are grouped into the Meals by their name and time of day in the saved order.
+func (t *MealTemplate) Meals(date time.Time) []Meal {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	var meals []Meal
+	index := make(map[string]int)
+	for _, item := range t.items {
+		meal_at := day.Add(time.Duration(item.time_of_day) * time.Minute)
+		key := item.meal_name + "@" + meal_at.Format(time.RFC3339)
+		i, ok := index[key]
+		if !ok {
+			meals = append(meals, *NewMeal(item.meal_name, &User{user_id: t.user_id}, meal_at, []MealItem{}, nil))
+			i = len(meals) - 1
+			index[key] = i
+		}
+		mi := NewMealItem(item.food_id, item.quantity, item.unit)
+		mi.recipe_version_id = item.recipe_version_id
+		meals[i].items = append(meals[i].items, *mi)
+	}
+	return meals
+}
+
+// ToString is return string of object
+func (t *MealTemplate) ToString() string {
+	return toString(t)
+}
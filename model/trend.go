@@ -0,0 +1,294 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// TrendDay defines struct of the calories and the macronutrients which a user has taken in a day.
+type TrendDay struct {
+	date      string    `json:"date"`
+	period    string    `json:"period"`
+	nutrients Nutrients `json:"nutrients"`
+	has_goal  bool      `json:"has_goal"`
+	adherent  bool      `json:"adherent"`
+}
+
+// TrendPeriod defines struct of the aggregation of the days in a bucket such as a week or a month.
+type TrendPeriod struct {
+	period        string    `json:"period"`
+	days          int       `json:"days"`
+	total         Nutrients `json:"total"`
+	average       Nutrients `json:"average"`
+	min_calories  float64   `json:"min_calories"`
+	max_calories  float64   `json:"max_calories"`
+	goal_days     int       `json:"goal_days"`
+	adherent_days int       `json:"adherent_days"`
+	adherence     float64   `json:"adherence"`
+}
+
+// TrendReport defines struct of the nutrition trend of a user.
+type TrendReport struct {
+	from          string        `json:"from"`
+	to            string        `json:"to"`
+	granularity   string        `json:"granularity"`
+	periods       []TrendPeriod `json:"periods"`
+	min_day       *TrendDay     `json:"min_day"`
+	max_day       *TrendDay     `json:"max_day"`
+	goal_days     int           `json:"goal_days"`
+	adherent_days int           `json:"adherent_days"`
+	adherence     float64       `json:"adherence"`
+}
+
+// RecordTrendDay defines struct represents the record of the daily aggregation.
+type RecordTrendDay struct {
+	day          string
+	period       string
+	calories     float64
+	protein      float64
+	carbohydrate float64
+	fat          float64
+	fiber        float64
+	sugar        float64
+	sodium       float64
+}
+
+// RecordTrendPeriod defines struct represents the record of the aggregation by the bucket.
+type RecordTrendPeriod struct {
+	period           string
+	days             int
+	calories         float64
+	protein          float64
+	carbohydrate     float64
+	fat              float64
+	fiber            float64
+	sugar            float64
+	sodium           float64
+	avg_calories     float64
+	avg_protein      float64
+	avg_carbohydrate float64
+	avg_fat          float64
+	avg_fiber        float64
+	avg_sugar        float64
+	avg_sodium       float64
+	min_calories     float64
+	max_calories     float64
+}
+
+// trendNutrients maps the aggregated columns to the snapshot columns of the meal_items and hydration_entries tables.
+var trendNutrients = [][2]string{
+	{"calories", "calo_amount"},
+	{"protein", "protein"},
+	{"carbohydrate", "carbohydrate"},
+	{"fat", "fat"},
+	{"fiber", "fiber"},
+	{"sugar", "sugar"},
+	{"sodium", "sodium"},
+}
+
+// NewTrendReport is constructor
+func NewTrendReport(from time.Time, to time.Time, granularity string) *TrendReport {
+	return &TrendReport{from: from.Format(util.DateLayout), to: to.Format(util.DateLayout),
+		granularity: granularity, periods: []TrendPeriod{}}
+}
+
+// FindByUserAndRange returns the trend of the meals which a given user has taken from a day to a day,
+// aggregated by the days and by the buckets of given granularity.
+// The beverages which are also Foods are included in the same way as the DailySummary.
+// The days start at midnight in the time zone of from, so a day of a DST transition has 23 or 25 hours.
+func (t *TrendReport) FindByUserAndRange(rep repository.Repository, user *User, from time.Time, to time.Time, granularity string) (*TrendReport, error) {
+	loc := from.Location()
+	start := util.StartOfDay(from)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	daily, args, err := dailyTrendSQL(rep, user, granularity, loc, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := NewTrendReport(from, to, granularity)
+	if err = report.findPeriods(rep, periodTrendSQL(daily), args); err != nil {
+		return nil, err
+	}
+
+	var days []TrendDay
+	if days, err = findTrendDays(rep, daily+" order by day", args); err != nil {
+		return nil, err
+	}
+
+	goal := Goal{}
+	goals, err := goal.FindByUser(rep, user)
+	if err != nil {
+		return nil, err
+	}
+	report.evaluate(days, *goals, loc)
+	return report, nil
+}
+
+// dailyTrendSQL returns the SQL which aggregates the meals and the beverages of given user by the days
+// in given time zone, and its arguments. The times are converted to the wall clock of the time zone first,
+// so that a meal late in the evening is in the day of the user rather than that of the database.
+// The plain beverages such as water have no nutrients and do not make a day by themselves.
+func dailyTrendSQL(rep repository.Repository, user *User, granularity string, loc *time.Location, start time.Time, end time.Time) (string, []interface{}, error) {
+	day, err := rep.DateBucket(repository.DAY, "t.local_at")
+	if err != nil {
+		return "", nil, err
+	}
+	period, err := rep.DateBucket(granularity, "t.local_at")
+	if err != nil {
+		return "", nil, err
+	}
+	mealAt, mealArgs := rep.LocalTime("m.meal_at", loc, start, end)
+	drankAt, drankArgs := rep.LocalTime("h.drank_at", loc, start, end)
+
+	items := []string{mealAt + " as local_at", "i.grams as grams"}
+	beverages := []string{drankAt + " as local_at", "h.volume as grams"}
+	columns := make([]string, len(trendNutrients))
+	for i, n := range trendNutrients {
+		items = append(items, fmt.Sprintf("i.%s as %s", n[1], n[0]))
+		beverages = append(beverages, fmt.Sprintf("h.%s as %s", n[1], n[0]))
+		columns[i] = fmt.Sprintf("sum(t.grams / 100 * t.%s) as %s", n[0], n[0])
+	}
+
+	args := append(append([]interface{}{}, mealArgs...), user.user_id, start.UTC(), end.UTC())
+	args = append(args, drankArgs...)
+	args = append(args, user.user_id, start.UTC(), end.UTC())
+	return "select " + day + " as day, " + period + " as period, " + strings.Join(columns, ", ") +
+		" from (select " + strings.Join(items, ", ") +
+		" from meals m inner join meal_items i on i.meal_id = m.meal_id" +
+		" where m.user_id = ? and m.meal_at >= ? and m.meal_at < ?" +
+		" union all select " + strings.Join(beverages, ", ") + " from hydration_entries h" +
+		" where h.user_id = ? and h.drank_at >= ? and h.drank_at < ? and h.food_id is not null) t" +
+		" group by " + day + ", " + period, args, nil
+}
+
+// periodTrendSQL returns the SQL which aggregates the result of given daily SQL by the buckets.
+func periodTrendSQL(daily string) string {
+	columns := []string{"d.period as period", "count(*) as days"}
+	for _, n := range trendNutrients {
+		columns = append(columns, fmt.Sprintf("sum(d.%s) as %s", n[0], n[0]))
+		columns = append(columns, fmt.Sprintf("avg(d.%s) as avg_%s", n[0], n[0]))
+	}
+	columns = append(columns, "min(d.calories) as min_calories", "max(d.calories) as max_calories")
+	return "select " + strings.Join(columns, ", ") + " from (" + daily + ") d group by d.period order by d.period"
+}
+
+func (t *TrendReport) findPeriods(rep repository.Repository, sqlquery string, args []interface{}) error {
+	var rec RecordTrendPeriod
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(sqlquery, args...).Rows(); err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return err
+		}
+		t.periods = append(t.periods, TrendPeriod{
+			period:       rec.period,
+			days:         rec.days,
+			total:        *NewNutrients(rec.calories, rec.protein, rec.carbohydrate, rec.fat, rec.fiber, rec.sugar, rec.sodium, nil),
+			average:      *NewNutrients(rec.avg_calories, rec.avg_protein, rec.avg_carbohydrate, rec.avg_fat, rec.avg_fiber, rec.avg_sugar, rec.avg_sodium, nil),
+			min_calories: rec.min_calories,
+			max_calories: rec.max_calories,
+		})
+	}
+	return nil
+}
+
+func findTrendDays(rep repository.Repository, sqlquery string, args []interface{}) ([]TrendDay, error) {
+	var days []TrendDay
+
+	var rec RecordTrendDay
+	var rows *sql.Rows
+	var err error
+
+	if rows, err = rep.Raw(sqlquery, args...).Rows(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rep.ScanRows(rows, &rec); err != nil {
+			return nil, err
+		}
+		days = append(days, TrendDay{date: rec.day, period: rec.period,
+			nutrients: *NewNutrients(rec.calories, rec.protein, rec.carbohydrate, rec.fat, rec.fiber, rec.sugar, rec.sodium, nil)})
+	}
+	return days, nil
+}
+
+// evaluate finds the days of the minimum and maximum calories and
+// evaluates the adherence of each day to the Goal which applied then.
+// A day is adherent when its calories are within the calories of the Goal.
+func (t *TrendReport) evaluate(days []TrendDay, goals []Goal, loc *time.Location) {
+	index := make(map[string]int, len(t.periods))
+	for i := range t.periods {
+		index[t.periods[i].period] = i
+	}
+
+	for i := range days {
+		day := &days[i]
+		if t.min_day == nil || day.nutrients.calories < t.min_day.nutrients.calories {
+			t.min_day = day
+		}
+		if t.max_day == nil || day.nutrients.calories > t.max_day.nutrients.calories {
+			t.max_day = day
+		}
+
+		goal := activeGoal(goals, day.date, loc)
+		if goal == nil {
+			continue
+		}
+		day.has_goal = true
+		day.adherent = day.nutrients.calories <= goal.calories
+
+		t.goal_days++
+		p, ok := index[day.period]
+		if ok {
+			t.periods[p].goal_days++
+		}
+		if day.adherent {
+			t.adherent_days++
+			if ok {
+				t.periods[p].adherent_days++
+			}
+		}
+	}
+
+	for i := range t.periods {
+		t.periods[i].adherence = ratio(t.periods[i].adherent_days, t.periods[i].goal_days)
+	}
+	t.adherence = ratio(t.adherent_days, t.goal_days)
+}
+
+// activeGoal returns the Goal which applies to given day in given time zone
+// from the history of the Goals sorted the newest first.
+func activeGoal(goals []Goal, date string, loc *time.Location) *Goal {
+	day, err := time.ParseInLocation(util.DateLayout, date, loc)
+	if err != nil {
+		return nil
+	}
+	next := day.AddDate(0, 0, 1)
+	for i := range goals {
+		if goals[i].effective_from.Before(next) {
+			return &goals[i]
+		}
+	}
+	return nil
+}
+
+func ratio(numerator int, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
@@ -1,17 +1,39 @@
 package model
 
 import (
+	"time"
+
+	"github.com/moznion/go-optional"
 	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // User defines struct of user data.
+// The allergens and the diets are the restrictions which the Meals of the user are checked against.
+// The days of the user, such as the daily summary, start at midnight in the IANA time zone of the user.
 type User struct {
-	user_id   uint   `gorm:"primary_key" json:"id"`
-	user_name string `json:"user_name"`
-	password  string `json:"-"`
+	user_id          uint       `gorm:"primary_key" json:"id"`
+	user_name        string     `json:"user_name"`
+	password         string     `json:"-"`
+	authority_id     uint       `json:"authority_id"`
+	height           float64    `json:"height"`
+	birth_date       *time.Time `json:"birth_date"`
+	sex              string     `json:"sex"`
+	activity_level   string     `json:"activity_level"`
+	timezone         string     `json:"timezone"`
+	restriction_mode string     `json:"restriction_mode"`
+	allergens        []string   `gorm:"-" json:"allergens"`
+	diets            []string   `gorm:"-" json:"diets"`
 }
 
+const (
+	// AuthorityAdmin represents the authority of the administrator who manages the food catalog.
+	AuthorityAdmin uint = 1
+	// AuthorityUser represents the authority of the general user.
+	AuthorityUser uint = 2
+)
+
 const selectUser = "select u.user_id as id, u.user_name as name, .password as password from users u"
 
 // TableName returns the table name of User struct and it is used by gorm.
@@ -27,13 +49,71 @@ func NewUser(user_name string, password string) *User {
 // NewUserWithPlainPassword is constructor. And it is encoded plain text password by using bcrypt.
 func NewUserWithPlainPassword(user_name string, password string, authorityID uint) *User {
 	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
-	return &User{user_name: user_name, password: string(hashed)}
+	return &User{user_name: user_name, password: string(hashed), authority_id: authorityID}
+}
+
+// FindByID returns a User full matched given User's ID.
+func (u *User) FindByID(rep repository.Repository, user_id uint) optional.Option[*User] {
+	var user User
+	if err := rep.Where("user_id = ?", user_id).First(&user).Error; err != nil {
+		return optional.None[*User]()
+	}
+	return optional.Some(&user)
 }
 
 // Create persists this User data.
 func (u *User) Create(rep repository.Repository) (*User, error) {
-	if err := rep.Select("user_name", "password").Create(u).Error; err != nil {
+	if err := rep.Select("user_name", "password", "authority_id").Create(u).Error; err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UpdateProfile updates the body profile and the time zone of this User.
+// The time zone is not changed if it is empty.
+func (u *User) UpdateProfile(rep repository.Repository, height float64, birth_date time.Time, sex string, activity_level string, timezone string) (*User, error) {
+	u.height = height
+	u.birth_date = &birth_date
+	u.sex = sex
+	u.activity_level = activity_level
+	columns := []string{"height", "birth_date", "sex", "activity_level"}
+	if timezone != "" {
+		u.timezone = timezone
+		columns = append(columns, "timezone")
+	}
+	if err := rep.Model(u).Select(columns).Updates(u).Error; err != nil {
 		return nil, err
 	}
 	return u, nil
 }
+
+// Location fetches the time zone of this User. It is the DefaultTimezone if the user has not configured it.
+func (u *User) Location(rep repository.Repository) *time.Location {
+	var user User
+	if err := rep.Select("timezone").Where("user_id = ?", u.user_id).First(&user).Error; err == nil {
+		u.timezone = user.timezone
+	}
+	return util.LoadLocation(u.timezone)
+}
+
+// IsAdmin returns true if this User is the administrator.
+func (u *User) IsAdmin() bool {
+	return u.authority_id == AuthorityAdmin
+}
+
+// ageAt returns the age of this User at given date.
+func (u *User) ageAt(date time.Time) int {
+	if u.birth_date == nil {
+		return 0
+	}
+	age := date.Year() - u.birth_date.Year()
+	if date.Month() < u.birth_date.Month() || (date.Month() == u.birth_date.Month() && date.Day() < u.birth_date.Day()) {
+		age--
+	}
+	return age
+}
+
+// hasProfile returns true if this User has all body profile fields to compute the energy expenditure.
+func (u *User) hasProfile() bool {
+	return u.height > 0 && u.birth_date != nil && u.sex != "" && u.activity_level != ""
+}
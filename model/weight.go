@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/moznion/go-optional"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+)
+
+// WeightEntry defines struct of the body weight of a user in kg.
+type WeightEntry struct {
+	weight_entry_id uint      `gorm:"primary_key" json:"id"`
+	user_id         uint      `json:"user_id"`
+	weight          float64   `json:"weight"`
+	measured_at     time.Time `json:"measured_at"`
+}
+
+// TableName returns the table name of WeightEntry struct and it is used by gorm.
+func (WeightEntry) TableName() string {
+	return "weight_entries"
+}
+
+// NewWeightEntry is constructor
+func NewWeightEntry(user *User, weight float64, measured_at time.Time) *WeightEntry {
+	return &WeightEntry{user_id: user.user_id, weight: weight, measured_at: measured_at}
+}
+
+// FindByID returns a WeightEntry of a given user full matched given ID.
+func (w *WeightEntry) FindByID(rep repository.Repository, user *User, id uint) optional.Option[*WeightEntry] {
+	var entry WeightEntry
+	if err := rep.Where("weight_entry_id = ? and user_id = ?", id, user.user_id).First(&entry).Error; err != nil {
+		return optional.None[*WeightEntry]()
+	}
+	return optional.Some(&entry)
+}
+
+// FindByUser returns the WeightEntries of a given user, the newest first.
+func (w *WeightEntry) FindByUser(rep repository.Repository, user *User) (*[]WeightEntry, error) {
+	var entries []WeightEntry
+	if err := rep.Where("user_id = ?", user.user_id).Order("measured_at desc").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return &entries, nil
+}
+
+// FindLatestByUserAndDate returns the latest WeightEntry of a given user measured until the end of a given day.
+func (w *WeightEntry) FindLatestByUserAndDate(rep repository.Repository, user *User, date time.Time) optional.Option[*WeightEntry] {
+	var entry WeightEntry
+	to := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).AddDate(0, 0, 1)
+	if err := rep.Where("user_id = ? and measured_at < ?", user.user_id, to).
+		Order("measured_at desc").First(&entry).Error; err != nil {
+		return optional.None[*WeightEntry]()
+	}
+	return optional.Some(&entry)
+}
+
+// Create persists this WeightEntry data.
+func (w *WeightEntry) Create(rep repository.Repository) (*WeightEntry, error) {
+	if err := rep.Select("user_id", "weight", "measured_at").Create(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Update updates the weight and the measured time of this WeightEntry.
+func (w *WeightEntry) Update(rep repository.Repository, weight float64, measured_at time.Time) (*WeightEntry, error) {
+	w.weight = weight
+	w.measured_at = measured_at
+	if err := rep.Model(w).Select("weight", "measured_at").Updates(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Delete deletes this WeightEntry data.
+func (w *WeightEntry) Delete(rep repository.Repository) (*WeightEntry, error) {
+	if err := rep.Delete(w).Error; err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// ToString is return string of object
+func (w *WeightEntry) ToString() string {
+	return toString(w)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DAY represents the bucket of a day.
+	DAY = "day"
+	// WEEK represents the bucket of a week which starts on Monday.
+	WEEK = "week"
+	// MONTH represents the bucket of a month.
+	MONTH = "month"
+)
+
+// DateBucket returns the SQL expression which truncates given column of the wall clock time
+// to the first day of the bucket, formatted as "YYYY-MM-DD" in every dialect.
+// The column of a stored time should be converted by LocalTime first, so that the buckets follow the user's time zone.
+func (rep *repository) DateBucket(granularity string, column string) (string, error) {
+	switch rep.dialect {
+	case POSTGRES:
+		switch granularity {
+		case DAY, WEEK, MONTH:
+			return fmt.Sprintf("to_char(date_trunc('%s', cast(%s as timestamp)), 'YYYY-MM-DD')", granularity, column), nil
+		}
+	case MYSQL:
+		switch granularity {
+		case DAY:
+			return fmt.Sprintf("date_format(%s, '%%Y-%%m-%%d')", column), nil
+		case WEEK:
+			return fmt.Sprintf("date_format(date_sub(%s, interval weekday(%s) day), '%%Y-%%m-%%d')", column, column), nil
+		case MONTH:
+			return fmt.Sprintf("date_format(%s, '%%Y-%%m-01')", column), nil
+		}
+	default:
+		switch granularity {
+		case DAY:
+			return fmt.Sprintf("date(%s)", column), nil
+		case WEEK:
+			return fmt.Sprintf("date(%s, '-6 days', 'weekday 1')", column), nil
+		case MONTH:
+			return fmt.Sprintf("strftime('%%Y-%%m-01', %s)", column), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported granularity: %s", granularity)
+}
+
+// LocalTime returns the SQL expression which converts given column of a stored time to the wall clock time
+// in given time zone, and the arguments of its placeholders. It is exact for the times from from to to,
+// including the DST transitions between them, and does not depend on the time zone tables of the database.
+// The times are stored in UTC, except that MySQL stores them in the local time zone of the application.
+func (rep *repository) LocalTime(column string, loc *time.Location, from time.Time, to time.Time) (string, []interface{}) {
+	stored := time.UTC
+	if rep.dialect == MYSQL {
+		stored = time.Local
+	}
+	shifts, untils := zoneShifts(loc, stored, from, to)
+	if len(untils) == 0 {
+		return rep.shiftTime(column, shifts[0]), nil
+	}
+
+	var b strings.Builder
+	args := make([]interface{}, len(untils))
+	b.WriteString("case")
+	for i := range untils {
+		fmt.Fprintf(&b, " when %s < ? then %s", column, rep.shiftTime(column, shifts[i]))
+		args[i] = untils[i]
+	}
+	fmt.Fprintf(&b, " else %s end", rep.shiftTime(column, shifts[len(shifts)-1]))
+	return b.String(), args
+}
+
+// shiftTime returns the SQL expression which adds given minutes to given column of a stored time.
+func (rep *repository) shiftTime(column string, minutes int) string {
+	switch rep.dialect {
+	case POSTGRES:
+		return fmt.Sprintf("(%s at time zone 'UTC' + interval '%d minutes')", column, minutes)
+	case MYSQL:
+		return fmt.Sprintf("date_add(%s, interval %d minute)", column, minutes)
+	}
+	return fmt.Sprintf("datetime(%s, '%+d minutes')", column, minutes)
+}
+
+// zoneShifts returns the differences in minutes of the wall clock of loc from that of stored from from to to,
+// and the times until which each of them but the last applies. The differences change at the DST transitions.
+func zoneShifts(loc *time.Location, stored *time.Location, from time.Time, to time.Time) ([]int, []time.Time) {
+	shift := func(t time.Time) int {
+		_, offset := t.In(loc).Zone()
+		_, base := t.In(stored).Zone()
+		return (offset - base) / 60
+	}
+
+	shifts := []int{shift(from)}
+	var untils []time.Time
+	// The transitions are at least hours apart, so they are sought by the hour and then by the second.
+	for t := from; t.Before(to); t = t.Add(time.Hour) {
+		next := t.Add(time.Hour)
+		if shift(next) == shifts[len(shifts)-1] {
+			continue
+		}
+		lo, hi := t, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if shift(mid) == shifts[len(shifts)-1] {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		// The transitions are on the second.
+		if s := hi.Truncate(time.Second); s.After(lo) {
+			hi = s
+		}
+		if !hi.Before(to) {
+			break
+		}
+		untils = append(untils, hi.UTC())
+		shifts = append(shifts, shift(hi))
+	}
+	return shifts, untils
+}
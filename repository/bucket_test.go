@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestZoneShifts(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, newYork)
+	to := time.Date(2024, 12, 1, 0, 0, 0, 0, newYork)
+
+	shifts, untils := zoneShifts(newYork, time.UTC, from, to)
+	if len(shifts) != 3 || shifts[0] != -300 || shifts[1] != -240 || shifts[2] != -300 {
+		t.Fatalf("shifts = %v, want [-300 -240 -300]", shifts)
+	}
+	want := []time.Time{
+		time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),
+	}
+	for i := range want {
+		if !untils[i].Equal(want[i]) {
+			t.Errorf("untils[%d] = %v, want %v", i, untils[i], want[i])
+		}
+	}
+
+	// A range without a transition has a single shift, and the zone of the stored times is subtracted.
+	shifts, untils = zoneShifts(time.FixedZone("JST", 9*60*60), time.FixedZone("CET", 60*60), from, to)
+	if len(untils) != 0 || len(shifts) != 1 || shifts[0] != 480 {
+		t.Errorf("fixed zones = %v, %v, want a single shift of 480", shifts, untils)
+	}
+}
+
+// TestDateBucketOfLocalTime buckets the times stored in UTC on SQLite by the days, weeks and months of the user,
+// around midnight and around the DST transitions.
+func TestDateBucketOfLocalTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	rep := &repository{db: db, dialect: SQLITE}
+	if err := rep.Exec("create table meals (meal_id integer primary key, meal_at datetime)").Error; err != nil {
+		t.Fatalf("create table error = %v", err)
+	}
+
+	meals := []struct {
+		at    time.Time
+		day   string
+		week  string
+		month string
+	}{
+		// 23:30 in New York is already the next day in UTC.
+		{time.Date(2024, 2, 29, 23, 30, 0, 0, newYork), "2024-02-29", "2024-02-26", "2024-02-01"},
+		{time.Date(2024, 3, 1, 0, 15, 0, 0, newYork), "2024-03-01", "2024-02-26", "2024-03-01"},
+		// Just before and after the spring forward on Sunday, March 10.
+		{time.Date(2024, 3, 10, 1, 59, 0, 0, newYork), "2024-03-10", "2024-03-04", "2024-03-01"},
+		{time.Date(2024, 3, 10, 23, 45, 0, 0, newYork), "2024-03-10", "2024-03-04", "2024-03-01"},
+		{time.Date(2024, 3, 11, 0, 5, 0, 0, newYork), "2024-03-11", "2024-03-11", "2024-03-01"},
+		// The last evening of the month after the fall back on November 3.
+		{time.Date(2024, 11, 30, 22, 0, 0, 0, newYork), "2024-11-30", "2024-11-25", "2024-11-01"},
+	}
+	for i, meal := range meals {
+		if err := rep.Exec("insert into meals (meal_id, meal_at) values (?, ?)", i+1, meal.at.UTC()).Error; err != nil {
+			t.Fatalf("insert error = %v", err)
+		}
+	}
+
+	from := time.Date(2024, 2, 1, 0, 0, 0, 0, newYork)
+	to := time.Date(2024, 12, 1, 0, 0, 0, 0, newYork)
+	local, args := rep.LocalTime("meal_at", newYork, from, to)
+	buckets := map[string]string{}
+	for _, granularity := range []string{DAY, WEEK, MONTH} {
+		bucket, err := rep.DateBucket(granularity, local)
+		if err != nil {
+			t.Fatalf("DateBucket(%s) error = %v", granularity, err)
+		}
+		buckets[granularity] = bucket
+	}
+
+	var rows []struct {
+		Day   string
+		Week  string
+		Month string
+	}
+	sqlquery := "select " + buckets[DAY] + " as day, " + buckets[WEEK] + " as week, " + buckets[MONTH] +
+		" as month from meals order by meal_id"
+	var all []interface{}
+	for range buckets {
+		all = append(all, args...)
+	}
+	if err := rep.Raw(sqlquery, all...).Scan(&rows).Error; err != nil {
+		t.Fatalf("select error = %v", err)
+	}
+	if len(rows) != len(meals) {
+		t.Fatalf("rows = %d, want %d", len(rows), len(meals))
+	}
+	for i, meal := range meals {
+		if rows[i].Day != meal.day || rows[i].Week != meal.week || rows[i].Month != meal.month {
+			t.Errorf("buckets of %v = %+v, want %s, %s, %s", meal.at, rows[i], meal.day, meal.week, meal.month)
+		}
+	}
+
+	if _, err := rep.DateBucket("year", "meal_at"); err == nil {
+		t.Error("DateBucket(year) error = nil, want unsupported granularity")
+	}
+}
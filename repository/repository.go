@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ybkuroki/go-webapp-sample/config"
 	"github.com/ybkuroki/go-webapp-sample/logger"
@@ -30,14 +31,24 @@ type Repository interface {
 	Scopes(funcs ...func(*gorm.DB) *gorm.DB) *gorm.DB
 	ScanRows(rows *sql.Rows, result interface{}) error
 	Transaction(fc func(tx Repository) error) (err error)
+	SavePoint(name string) error
+	RollbackTo(name string) error
 	Close() error
 	DropTableIfExists(value interface{}) error
 	AutoMigrate(value interface{}) error
+	HasColumn(value interface{}, column string) bool
+	HasIndex(value interface{}, name string) bool
+	DateBucket(granularity string, column string) (string, error)
+	LocalTime(column string, loc *time.Location, from time.Time, to time.Time) (string, []interface{})
+	SetupTextSearch(table string, key string, column string) error
+	MatchText(table string, key string, column string, query string) (string, []interface{}, error)
 }
 
 // repository defines a repository for access the database.
 type repository struct {
-	db *gorm.DB
+	db         *gorm.DB
+	dialect    string
+	textSearch bool
 }
 
 // MealRepository is a concrete repository that implements repository.
@@ -54,7 +65,7 @@ func NewMealRepository(logger logger.Logger, conf *config.Config) Repository {
 		os.Exit(2)
 	}
 	logger.GetZapLogger().Infof("Success database connection, %s:%s", conf.Database.Host, conf.Database.Port)
-	return &mealRepository{&repository{db: db}}
+	return &mealRepository{&repository{db: db, dialect: conf.Database.Dialect}}
 }
 
 const (
@@ -166,6 +177,16 @@ func (rep *repository) AutoMigrate(value interface{}) error {
 	return rep.db.AutoMigrate(value)
 }
 
+// HasColumn returns true if the table of given model has the column
+func (rep *repository) HasColumn(value interface{}, column string) bool {
+	return rep.db.Migrator().HasColumn(value, column)
+}
+
+// HasIndex returns true if the table of given model has the index of given name
+func (rep *repository) HasIndex(value interface{}, name string) bool {
+	return rep.db.Migrator().HasIndex(value, name)
+}
+
 // Transaction start a transaction as a block.
 // If it is failed, will rollback and return error.
 // If it is sccuessed, will commit.
@@ -181,6 +202,8 @@ func (rep *repository) Transaction(fc func(tx Repository) error) (err error) {
 
 	txrep := &repository{}
 	txrep.db = tx
+	txrep.dialect = rep.dialect
+	txrep.textSearch = rep.textSearch
 	err = fc(txrep)
 
 	if err == nil {
@@ -190,3 +213,13 @@ func (rep *repository) Transaction(fc func(tx Repository) error) (err error) {
 	panicked = false
 	return
 }
+
+// SavePoint marks a point in the current transaction which can be rolled back to by RollbackTo.
+func (rep *repository) SavePoint(name string) error {
+	return rep.db.SavePoint(name).Error
+}
+
+// RollbackTo undoes the changes in the current transaction after the save point of given name.
+func (rep *repository) RollbackTo(name string) error {
+	return rep.db.RollbackTo(name).Error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTextSearchUnavailable is returned by MatchText when the database has no index for the text search,
+// e.g. SQLite built without FTS5 or PostgreSQL without pg_trgm.
+var ErrTextSearchUnavailable = errors.New("text search is unavailable")
+
+// prefixBoost is added to the score of the rows which start with the query so that they come first as autocomplete.
+const prefixBoost = 100
+
+// SetupTextSearch creates the trigram index on given column of given table for the dialect.
+// SQLite uses an FTS5 table with the trigram tokenizer which is kept in sync by triggers,
+// PostgreSQL uses pg_trgm and MySQL uses a FULLTEXT index with the ngram parser.
+// If it returns an error, MatchText returns ErrTextSearchUnavailable and the caller should search without the index.
+func (rep *repository) SetupTextSearch(table string, key string, column string) error {
+	var statements []string
+	switch rep.dialect {
+	case POSTGRES:
+		statements = []string{
+			"create extension if not exists pg_trgm",
+			fmt.Sprintf("create index if not exists idx_%s_%s_trgm on %s using gin (%s gin_trgm_ops)", table, column, table, column),
+		}
+	case MYSQL:
+		index := fmt.Sprintf("idx_%s_%s_ngram", table, column)
+		if !rep.db.Migrator().HasIndex(table, index) {
+			statements = []string{fmt.Sprintf("alter table %s add fulltext index %s (%s) with parser ngram", table, index, column)}
+		}
+	default:
+		fts := table + "_fts"
+		statements = []string{
+			fmt.Sprintf("create virtual table if not exists %s using fts5(%s, content='%s', content_rowid='%s', tokenize='trigram')", fts, column, table, key),
+			fmt.Sprintf("create trigger if not exists %s_ai after insert on %s begin "+
+				"insert into %s(rowid, %s) values (new.%s, new.%s); end", fts, table, fts, column, key, column),
+			fmt.Sprintf("create trigger if not exists %s_ad after delete on %s begin "+
+				"insert into %s(%s, rowid, %s) values ('delete', old.%s, old.%s); end", fts, table, fts, fts, column, key, column),
+			fmt.Sprintf("create trigger if not exists %s_au after update on %s begin "+
+				"insert into %s(%s, rowid, %s) values ('delete', old.%s, old.%s); "+
+				"insert into %s(rowid, %s) values (new.%s, new.%s); end", fts, table, fts, fts, column, key, column, fts, column, key, column),
+			fmt.Sprintf("insert into %s(%s) values ('rebuild')", fts, fts),
+		}
+	}
+
+	for _, statement := range statements {
+		if err := rep.db.Exec(statement).Error; err != nil {
+			rep.textSearch = false
+			return err
+		}
+	}
+	rep.textSearch = true
+	return nil
+}
+
+// MatchText returns the SQL which selects the keys as "id" and the relevance as "score" of the rows of given table
+// whose column is similar to given query, and its arguments. The higher score is the more relevant.
+// The query should have at least 3 characters because the index consists of trigrams.
+func (rep *repository) MatchText(table string, key string, column string, query string) (string, []interface{}, error) {
+	if !rep.textSearch {
+		return "", nil, ErrTextSearchUnavailable
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(query))
+	switch rep.dialect {
+	case POSTGRES:
+		return fmt.Sprintf("select %s as id, word_similarity(?, %s) + case when lower(%s) like ? then %d else 0 end as score "+
+				"from %s where ? <%% %s or lower(%s) like ?", key, column, column, prefixBoost, table, column, column),
+			[]interface{}{lower, lower + "%", lower, "%" + lower + "%"}, nil
+	case MYSQL:
+		return fmt.Sprintf("select %s as id, match(%s) against (?) + case when lower(%s) like ? then %d else 0 end as score "+
+				"from %s where match(%s) against (?)", key, column, column, prefixBoost, table, column),
+			[]interface{}{lower, lower + "%", lower}, nil
+	default:
+		fts := table + "_fts"
+		return fmt.Sprintf("select rowid as id, -bm25(%s) + case when lower(%s) like ? then %d else 0 end as score "+
+				"from %s where %s match ?", fts, column, prefixBoost, fts, fts),
+			[]interface{}{lower + "%", trigramQuery(lower)}, nil
+	}
+}
+
+// trigramQuery returns the FTS5 query which matches any trigram of given text.
+// The rows sharing more trigrams rank higher by bm25, so that a misspelt query still finds the right rows.
+func trigramQuery(text string) string {
+	runes := []rune(text)
+	seen := make(map[string]bool)
+	var terms []string
+	for i := 0; i+3 <= len(runes); i++ {
+		gram := string(runes[i : i+3])
+		if seen[gram] {
+			continue
+		}
+		seen[gram] = true
+		terms = append(terms, `"`+strings.ReplaceAll(gram, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " OR ")
+}
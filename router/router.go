@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/ybkuroki/go-webapp-sample/container"
 	"github.com/ybkuroki/go-webapp-sample/controller"
+	appmiddleware "github.com/ybkuroki/go-webapp-sample/middleware"
 
 	_ "github.com/ybkuroki/go-webapp-sample/docs" // for using echo-swagger
 )
@@ -18,6 +19,17 @@ func Init(e *echo.Echo, container container.Container) {
 	setErrorController(e, container)
 	setMealController(e, container)
 	setFoodController(e, container)
+	setSummaryController(e, container)
+	setGoalController(e, container)
+	setReportController(e, container)
+	setWeightController(e, container)
+	setActivityController(e, container)
+	setHydrationController(e, container)
+	setRecipeController(e, container)
+	setMealTemplateController(e, container)
+	setMealPlanController(e, container)
+	setShoppingListController(e, container)
+	setUserController(e, container)
 }
 
 func setCORSConfig(e *echo.Echo, container container.Container) {
@@ -53,19 +65,120 @@ func setMealController(e *echo.Echo, container container.Container) {
 	e.GET(controller.APIMealsID, func(c echo.Context) error { return Meal.GetMeal(c) })
 	e.GET(controller.APIMeals, func(c echo.Context) error { return Meal.GetMealList(c) })
 	e.POST(controller.APIMeals, func(c echo.Context) error { return Meal.CreateMeal(c) })
+	e.POST(controller.APIMealsCopy, func(c echo.Context) error { return Meal.CopyMeals(c) })
+	e.POST(controller.APIMealsBatch, func(c echo.Context) error { return Meal.BatchMeals(c) })
+	e.POST(controller.APIMealsRecalculate, func(c echo.Context) error { return Meal.RecalculateMeal(c) })
 	e.PUT(controller.APIMealsID, func(c echo.Context) error { return Meal.UpdateMeal(c) })
+	e.PATCH(controller.APIMealsID, func(c echo.Context) error { return Meal.PatchMeal(c) })
 	e.DELETE(controller.APIMealsID, func(c echo.Context) error { return Meal.DeleteMeal(c) })
 }
 
 func setFoodController(e *echo.Echo, container container.Container) {
-	food := controller.NewfoodController(container)
-	e.GET(controller.APICategories, func(c echo.Context) error { return food.GetfoodList(c) })
+	food := controller.NewFoodController(container)
+	e.GET(controller.APIFoodsID, func(c echo.Context) error { return food.GetFood(c) })
+	e.GET(controller.APIFoods, func(c echo.Context) error { return food.GetFoodList(c) })
+	e.POST(controller.APIFoods, func(c echo.Context) error { return food.CreateFood(c) })
+	e.PUT(controller.APIFoodsID, func(c echo.Context) error { return food.UpdateFood(c) })
+	e.DELETE(controller.APIFoodsID, func(c echo.Context) error { return food.DeleteFood(c) })
+	e.GET(controller.APIFoodsFrequent, func(c echo.Context) error { return food.GetFrequentFoods(c) })
+	e.GET(controller.APIFoodsBarcode, func(c echo.Context) error { return food.GetFoodByBarcode(c) })
+
+	// The global catalog is maintained only by the administrator.
+	admin := appmiddleware.AdminMiddleware(container)
+	e.PUT(controller.APIFoodsTags, func(c echo.Context) error { return food.UpdateFoodTags(c) }, admin)
+	e.PUT(controller.APIFoodsSizes, func(c echo.Context) error { return food.UpdateFoodSizes(c) }, admin)
+	e.POST(controller.APIFoodsMerge, func(c echo.Context) error { return food.MergeFoods(c) }, admin)
+	e.POST(controller.APIFoodsImport, func(c echo.Context) error { return food.ImportFoods(c) }, admin)
+}
+
+func setSummaryController(e *echo.Echo, container container.Container) {
+	summary := controller.NewSummaryController(container)
+	e.GET(controller.APISummaryDaily, func(c echo.Context) error { return summary.GetDailySummary(c) })
+}
+
+func setGoalController(e *echo.Echo, container container.Container) {
+	goal := controller.NewGoalController(container)
+	e.GET(controller.APIGoals, func(c echo.Context) error { return goal.GetGoalList(c) })
+	e.PUT(controller.APIGoals, func(c echo.Context) error { return goal.UpdateGoal(c) })
+	e.GET(controller.APIGoalsProgress, func(c echo.Context) error { return goal.GetProgress(c) })
+}
+
+func setReportController(e *echo.Echo, container container.Container) {
+	report := controller.NewReportController(container)
+	e.GET(controller.APIReportsTrends, func(c echo.Context) error { return report.GetTrends(c) })
+}
+
+func setWeightController(e *echo.Echo, container container.Container) {
+	weight := controller.NewWeightController(container)
+	e.GET(controller.APIWeightsID, func(c echo.Context) error { return weight.GetWeight(c) })
+	e.GET(controller.APIWeights, func(c echo.Context) error { return weight.GetWeightList(c) })
+	e.POST(controller.APIWeights, func(c echo.Context) error { return weight.CreateWeight(c) })
+	e.PUT(controller.APIWeightsID, func(c echo.Context) error { return weight.UpdateWeight(c) })
+	e.DELETE(controller.APIWeightsID, func(c echo.Context) error { return weight.DeleteWeight(c) })
+}
+
+func setActivityController(e *echo.Echo, container container.Container) {
+	activity := controller.NewActivityController(container)
+	e.GET(controller.APIActivityTypes, func(c echo.Context) error { return activity.GetActivityTypeList(c) })
+	e.GET(controller.APIActivitiesID, func(c echo.Context) error { return activity.GetActivity(c) })
+	e.GET(controller.APIActivities, func(c echo.Context) error { return activity.GetActivityList(c) })
+	e.POST(controller.APIActivities, func(c echo.Context) error { return activity.CreateActivity(c) })
+	e.PUT(controller.APIActivitiesID, func(c echo.Context) error { return activity.UpdateActivity(c) })
+	e.DELETE(controller.APIActivitiesID, func(c echo.Context) error { return activity.DeleteActivity(c) })
+}
+
+func setHydrationController(e *echo.Echo, container container.Container) {
+	hydration := controller.NewHydrationController(container)
+	e.GET(controller.APIHydrationDaily, func(c echo.Context) error { return hydration.GetDailyHydration(c) })
+	e.GET(controller.APIHydration, func(c echo.Context) error { return hydration.GetHydrationList(c) })
+	e.POST(controller.APIHydration, func(c echo.Context) error { return hydration.CreateHydration(c) })
+	e.DELETE(controller.APIHydrationID, func(c echo.Context) error { return hydration.DeleteHydration(c) })
+}
+
+func setRecipeController(e *echo.Echo, container container.Container) {
+	recipe := controller.NewRecipeController(container)
+	e.GET(controller.APIRecipesID, func(c echo.Context) error { return recipe.GetRecipe(c) })
+	e.GET(controller.APIRecipes, func(c echo.Context) error { return recipe.GetRecipeList(c) })
+	e.POST(controller.APIRecipes, func(c echo.Context) error { return recipe.CreateRecipe(c) })
+	e.PUT(controller.APIRecipesID, func(c echo.Context) error { return recipe.UpdateRecipe(c) })
+}
+
+func setMealTemplateController(e *echo.Echo, container container.Container) {
+	template := controller.NewMealTemplateController(container)
+	e.GET(controller.APITemplates, func(c echo.Context) error { return template.GetTemplateList(c) })
+	e.POST(controller.APITemplates, func(c echo.Context) error { return template.CreateTemplate(c) })
+	e.DELETE(controller.APITemplatesID, func(c echo.Context) error { return template.DeleteTemplate(c) })
+	e.POST(controller.APITemplatesLog, func(c echo.Context) error { return template.LogTemplate(c) })
+	e.POST(controller.APITemplatesFavorite, func(c echo.Context) error { return template.AddFavorite(c) })
+	e.DELETE(controller.APITemplatesFavorite, func(c echo.Context) error { return template.RemoveFavorite(c) })
+}
+
+func setMealPlanController(e *echo.Echo, container container.Container) {
+	plan := controller.NewMealPlanController(container)
+	e.GET(controller.APIPlans, func(c echo.Context) error { return plan.GetCalendar(c) })
+	e.POST(controller.APIPlans, func(c echo.Context) error { return plan.CreatePlan(c) })
+	e.DELETE(controller.APIPlansID, func(c echo.Context) error { return plan.DeletePlan(c) })
+	e.POST(controller.APIPlansEat, func(c echo.Context) error { return plan.EatPlan(c) })
+	e.POST(controller.APIPlansSkip, func(c echo.Context) error { return plan.SkipPlan(c) })
+}
+
+func setShoppingListController(e *echo.Echo, container container.Container) {
+	list := controller.NewShoppingListController(container)
+	e.GET(controller.APIShoppingListsID, func(c echo.Context) error { return list.GetShoppingList(c) })
+	e.GET(controller.APIShoppingLists, func(c echo.Context) error { return list.GetShoppingListList(c) })
+	e.POST(controller.APIShoppingLists, func(c echo.Context) error { return list.CreateShoppingList(c) })
+	e.PUT(controller.APIShoppingListsItem, func(c echo.Context) error { return list.CheckItem(c) })
+	e.DELETE(controller.APIShoppingListsID, func(c echo.Context) error { return list.DeleteShoppingList(c) })
 }
 
 func setUserController(e *echo.Echo, container container.Container) {
 	user := controller.NewUserController(container)
 	e.GET(controller.APIUserLoginStatus, func(c echo.Context) error { return user.GetLoginStatus(c) })
 	e.GET(controller.APIUserLoginUser, func(c echo.Context) error { return user.GetLoginUser(c) })
+	e.GET(controller.APIProfile, func(c echo.Context) error { return user.GetProfile(c) })
+	e.PUT(controller.APIProfile, func(c echo.Context) error { return user.UpdateProfile(c) })
+	e.GET(controller.APIRestrictions, func(c echo.Context) error { return user.GetRestrictions(c) })
+	e.PUT(controller.APIRestrictions, func(c echo.Context) error { return user.UpdateRestrictions(c) })
 
 	if container.GetConfig().Extension.SecurityEnabled {
 		e.POST(controller.APIUserLogin, func(c echo.Context) error { return user.Login(c) })
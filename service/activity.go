@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// ActivityService is a service for managing the activities of the logged-in user.
+type ActivityService interface {
+	FindByID(id string) (*model.Activity, error)
+	FindAllActivities() (*[]model.Activity, error)
+	FindAllActivityTypes() *[]model.ActivityType
+	CreateActivity(dto *dto.ActivityDto) (*model.Activity, map[string]string)
+	UpdateActivity(dto *dto.ActivityDto, id string) (*model.Activity, map[string]string)
+	DeleteActivity(id string) (*model.Activity, map[string]string)
+}
+
+type activityService struct {
+	container container.Container
+}
+
+// NewActivityService is constructor.
+func NewActivityService(container container.Container) ActivityService {
+	return &activityService{container: container}
+}
+
+// FindByID returns one record of the logged-in user matched activity's id.
+func (a *activityService) FindByID(id string) (*model.Activity, error) {
+	user := a.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := a.container.GetRepository()
+	activity := model.Activity{}
+	result, err := activity.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAllActivities returns the list of all activities of the logged-in user.
+func (a *activityService) FindAllActivities() (*[]model.Activity, error) {
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := a.container.GetRepository()
+	activity := model.Activity{}
+	result, err := activity.FindByUser(rep, user)
+	if err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAllActivityTypes returns the list of all activity types with their MET.
+func (a *activityService) FindAllActivityTypes() *[]model.ActivityType {
+	rep := a.container.GetRepository()
+	activityType := model.ActivityType{}
+	result, err := activityType.FindAll(rep)
+	if err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil
+	}
+	return result
+}
+
+// CreateActivity register the given activity data.
+func (a *activityService) CreateActivity(dto *dto.ActivityDto) (*model.Activity, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := a.container.GetRepository()
+	var result *model.Activity
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user).Create(txrep)
+		return err
+	}); trerr != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// UpdateActivity updates the given activity data.
+func (a *activityService) UpdateActivity(dto *dto.ActivityDto, id string) (*model.Activity, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := a.container.GetSession().GetUser()
+	activity, err := a.FindByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := a.container.GetRepository()
+	var result *model.Activity
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = activity.Update(txrep, dto.Create(user))
+		return err
+	}); trerr != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteActivity deletes the given activity data.
+func (a *activityService) DeleteActivity(id string) (*model.Activity, map[string]string) {
+	activity, err := a.FindByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := a.container.GetRepository()
+	result, err := activity.Delete(rep)
+	if err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
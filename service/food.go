@@ -1,15 +1,50 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/ybkuroki/go-webapp-sample/container"
 	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
 )
 
 // FoodService is a service for managing master data such as format and food.
 type FoodService interface {
 	FindAllFoods() *[]model.Food
+	FindFood(id string) (*model.Food, error)
+	CreateFood(dto *dto.FoodDto) (*model.Food, map[string]string)
+	UpdateFood(id string, dto *dto.FoodDto) (*model.Food, map[string]string)
+	DeleteFood(id string) (*model.Food, map[string]string)
+	MergeFoods(id string, dto *dto.FoodMergeDto) (*model.Food, map[string]string)
+	FindFoodByBarcode(code string) (*model.Food, error)
+	SearchFoods(query string, exclude string, diet string, page string, size string) (*model.Page, error)
+	UpdateFoodTags(id string, dto *dto.FoodTagDto) (*model.Food, map[string]string)
+	UpdateFoodSizes(id string, dto *dto.FoodSizeDto) (*model.Food, map[string]string)
+	FindFrequentFoods(limit string, days string) (*[]model.FrequentFood, error)
+	ImportFoods(path string, format string) (*model.ImportResult, error)
+	ImportFoodsFromDirectory(file string, format string) (*model.ImportResult, error)
 }
 
+var (
+	// ErrInvalidBarcode is returned when a barcode is not a valid EAN-13 or UPC-A.
+	ErrInvalidBarcode = errors.New("invalid barcode")
+	// ErrFoodNotFound is returned when no food is matched.
+	ErrFoodNotFound = errors.New("food not found")
+)
+
+const (
+	// defaultSearchSize is the number of foods per page of the search when the page is not given.
+	defaultSearchSize = "20"
+	// defaultFrequentLimit is the number of the frequent foods when the limit is not given.
+	defaultFrequentLimit = 10
+)
+
 type foodService struct {
 	container container.Container
 }
@@ -19,14 +54,352 @@ func NewFoodService(container container.Container) FoodService {
 	return &foodService{container: container}
 }
 
-// FindAllFoods returns the list of all foods.
+// FindAllFoods returns the list of the foods of the global catalog and the private foods of the logged-in user.
 func (m *foodService) FindAllFoods() *[]model.Food {
 	rep := m.container.GetRepository()
 	food := model.Food{}
-	result, err := food.FindAll(rep)
+	result, err := food.FindByUser(rep, m.container.GetSession().GetUser())
 	if err != nil {
 		m.container.GetLogger().GetZapLogger().Errorf(err.Error())
 		return nil
 	}
 	return result
 }
+
+// FindFood returns the food of given ID if it is in the global catalog or belongs to the logged-in user.
+func (m *foodService) FindFood(id string) (*model.Food, error) {
+	if !util.IsNumeric(id) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	result, err := food.FindVisibleByID(rep, m.container.GetSession().GetUser(), util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, errors.New("failed to fetch data")
+	}
+	return result, nil
+}
+
+// CreateFood registers the private food of the logged-in user. The administrator can register the food of the global catalog.
+func (m *foodService) CreateFood(dto *dto.FoodDto) (*model.Food, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if errors := dto.ValidateFor(user, m.container.GetConfig().Nutrient.Micronutrients); errors != nil {
+		return nil, errors
+	}
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := m.container.GetRepository()
+	var result *model.Food
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user).Create(txrep)
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// UpdateFood updates the food of given ID. A user can update only the own foods and
+// the administrator can update the foods of the global catalog.
+func (m *foodService) UpdateFood(id string, dto *dto.FoodDto) (*model.Food, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if errors := dto.ValidateFor(user, m.container.GetConfig().Nutrient.Micronutrients); errors != nil {
+		return nil, errors
+	}
+	if !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	var result *model.Food
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		if result, err = food.FindByID(txrep, util.ConvertToUint(id)).Take(); err != nil {
+			return err
+		}
+		if !result.IsEditableBy(user) {
+			return errors.New("the food is not editable by the user")
+		}
+		result, err = result.Edit(txrep, dto.Create(user))
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteFood deletes the food of given ID. It is soft-deleted if the meals, the recipes or the plans use it.
+// A user can delete only the own foods and the administrator can delete the foods of the global catalog.
+func (m *foodService) DeleteFood(id string) (*model.Food, map[string]string) {
+	if !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	user := m.container.GetSession().GetUser()
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	var result *model.Food
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		if result, err = food.FindByID(txrep, util.ConvertToUint(id)).Take(); err != nil {
+			return err
+		}
+		if !result.IsEditableBy(user) {
+			return errors.New("the food is not editable by the user")
+		}
+		result, err = result.Delete(txrep)
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
+
+// MergeFoods merges the given duplicate food into the food of given ID. All meals, recipes and plans
+// which use the duplicate are re-pointed to the food and the duplicate is deleted. Both must be in the global catalog.
+func (m *foodService) MergeFoods(id string, dto *dto.FoodMergeDto) (*model.Food, map[string]string) {
+	if !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	if errors := dto.ValidateFor(util.ConvertToUint(id)); errors != nil {
+		return nil, errors
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	var result *model.Food
+	var messages map[string]string
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		survivor, err := food.FindByID(txrep, util.ConvertToUint(id)).Take()
+		if err != nil {
+			return err
+		}
+		duplicate, _ := food.FindByID(txrep, dto.DuplicateID()).Take()
+		if messages = dto.ValidateFoods(survivor, duplicate); messages != nil {
+			return errors.New("the foods cannot be merged")
+		}
+		result, err = survivor.Merge(txrep, duplicate)
+		return err
+	}); trerr != nil {
+		if messages != nil {
+			return nil, messages
+		}
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// FindFoodByBarcode returns the packaged food matched given EAN-13 or UPC-A barcode.
+// It returns ErrInvalidBarcode if the barcode is malformed and ErrFoodNotFound if no food has the barcode.
+func (m *foodService) FindFoodByBarcode(code string) (*model.Food, error) {
+	barcode, err := util.NormalizeBarcode(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBarcode, err.Error())
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	result, err := food.FindByBarcode(rep, barcode).Take()
+	if err != nil {
+		return nil, ErrFoodNotFound
+	}
+	return result, nil
+}
+
+// SearchFoods returns the page object of the foods similar to given query in order of relevance.
+// The foods of the global catalog and the private foods of the logged-in user are searched.
+// The foods which contain any of the comma separated allergens of exclude or are not suitable for
+// all of the comma separated diets of diet are excluded.
+// It returns the first page of 20 foods if the page is not given.
+func (m *foodService) SearchFoods(query string, exclude string, diet string, page string, size string) (*model.Page, error) {
+	if !util.IsNumeric(page) || !util.IsNumeric(size) {
+		page, size = "0", defaultSearchSize
+	}
+	allergens, diets := splitTags(exclude), splitTags(diet)
+	if !containsAll(model.Allergens, allergens) || !containsAll(model.Diets, diets) {
+		return nil, errors.New("unknown allergen or diet")
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	result, err := food.Search(rep, query, model.NewFoodFilter(m.container.GetSession().GetUser(), allergens, diets), page, size)
+	if err != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, errors.New("failed to search foods")
+	}
+	return result, nil
+}
+
+// UpdateFoodTags replaces the allergen and the diet tags of the food of given ID.
+// Only the foods of the global catalog can be changed, and the private foods are changed by their owners with UpdateFood.
+func (m *foodService) UpdateFoodTags(id string, dto *dto.FoodTagDto) (*model.Food, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+	if !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	var result *model.Food
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		if result, err = food.FindByID(txrep, util.ConvertToUint(id)).Take(); err != nil {
+			return err
+		}
+		if !result.IsGlobal() {
+			return errors.New("the food is not in the global catalog")
+		}
+		result, err = result.SetTags(txrep, dto.Allergens(), dto.Diets())
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// UpdateFoodSizes updates the grams of a serving and a piece and the density of the food of given ID.
+// Only the foods of the global catalog can be changed, and the private foods are changed by their owners with UpdateFood.
+func (m *foodService) UpdateFoodSizes(id string, dto *dto.FoodSizeDto) (*model.Food, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+	if !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	food := model.Food{}
+	var result *model.Food
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		if result, err = food.FindByID(txrep, util.ConvertToUint(id)).Take(); err != nil {
+			return err
+		}
+		if !result.IsGlobal() {
+			return errors.New("the food is not in the global catalog")
+		}
+		result, err = result.UpdateSizes(txrep, dto.ServingSize(), dto.PieceSize(), dto.Density())
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// splitTags returns the tags in given comma separated string.
+func splitTags(tags string) []string {
+	var result []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// containsAll returns true if all of given tags are in the known tags.
+func containsAll(known []string, tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, k := range known {
+			if k == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FindFrequentFoods returns the foods which the logged-in user has logged most frequently in the meal history.
+// If days is given, only the meals in the last days are counted.
+func (m *foodService) FindFrequentFoods(limit string, days string) (*[]model.FrequentFood, error) {
+	user := m.container.GetSession().GetUser()
+	if user == nil || (limit != "" && !util.IsNumeric(limit)) || (days != "" && !util.IsNumeric(days)) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	n := defaultFrequentLimit
+	if util.ConvertToInt(limit) > 0 {
+		n = util.ConvertToInt(limit)
+	}
+	since := time.Time{}
+	if util.ConvertToInt(days) > 0 {
+		since = time.Now().AddDate(0, 0, -util.ConvertToInt(days))
+	}
+
+	rep := m.container.GetRepository()
+	frequent := model.FrequentFood{}
+	result, err := frequent.FindByUser(rep, user, since, n)
+	if err != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// ImportFoods imports the foods from a CSV file or a JSON file of USDA FoodData Central on local disk.
+// The format is detected by the extension of the file if it is omitted.
+// All foods are imported in one transaction, so a bad file does not leave a part of the foods.
+func (m *foodService) ImportFoods(path string, format string) (*model.ImportResult, error) {
+	logger := m.container.GetLogger()
+
+	format, err := detectImportFormat(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.NewImportResult(filepath.Base(path), format)
+	foods, err := readFoodFile(path, format, m.container.GetConfig().Nutrient.Micronutrients, result)
+	if err != nil {
+		logger.GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+
+	rep := m.container.GetRepository()
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		return result.Import(txrep, foods, importBatchSize)
+	}); trerr != nil {
+		logger.GetZapLogger().Errorf(trerr.Error())
+		return nil, errors.New("failed to import the foods")
+	}
+
+	logger.GetZapLogger().Infof("Imported foods from " + result.Summary())
+	return result, nil
+}
+
+// ImportFoodsFromDirectory imports the foods from a file in the import directory of the configuration.
+func (m *foodService) ImportFoodsFromDirectory(file string, format string) (*model.ImportResult, error) {
+	dir := m.container.GetConfig().Extension.FoodImportDir
+	if dir == "" {
+		return nil, errors.New("the import directory is not configured")
+	}
+
+	path := filepath.Join(dir, filepath.Clean("/"+file))
+	if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+		return nil, errors.New("the file must be in the import directory")
+	}
+	return m.ImportFoods(path, format)
+}
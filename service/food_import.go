@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	// ImportFormatCSV represents the CSV file which has a header row.
+	ImportFormatCSV = "csv"
+	// ImportFormatUSDA represents the JSON file of USDA FoodData Central.
+	ImportFormatUSDA = "usda"
+	// ImportFormatOFF represents the JSONL or CSV dump of Open Food Facts.
+	ImportFormatOFF = "off"
+)
+
+// importBatchSize is the number of foods which are imported at once.
+const importBatchSize = 100
+
+// csvColumns maps the header names of the CSV file to the nutrients.
+// The columns of the micronutrients are the names in the configuration.
+var csvColumns = []string{"name", "calories", "protein", "carbohydrate", "fat", "fiber", "sugar", "sodium"}
+
+// usdaEnergies are the nutrient numbers of the energy in kcal of USDA FoodData Central in order of preference.
+var usdaEnergies = []string{"208", "957", "958"}
+
+// usdaNutrients maps the nutrient numbers of USDA FoodData Central to the nutrients.
+var usdaNutrients = map[string]string{
+	"203": "protein",
+	"205": "carbohydrate",
+	"204": "fat",
+	"291": "fiber",
+	"269": "sugar",
+	"307": "sodium",
+	"401": "vitamin_c",
+	"301": "calcium",
+	"303": "iron",
+	"306": "potassium",
+}
+
+// offNutrients maps the nutriment keys per 100 g of Open Food Facts to the nutrients.
+// Open Food Facts has the amounts of sodium and minerals in grams, so they are converted to milligrams.
+var offNutrients = map[string]struct {
+	name   string
+	factor float64
+}{
+	"proteins_100g":      {"protein", 1},
+	"carbohydrates_100g": {"carbohydrate", 1},
+	"fat_100g":           {"fat", 1},
+	"fiber_100g":         {"fiber", 1},
+	"sugars_100g":        {"sugar", 1},
+	"sodium_100g":        {"sodium", 1000},
+	"vitamin-c_100g":     {"vitamin_c", 1000},
+	"calcium_100g":       {"calcium", 1000},
+	"iron_100g":          {"iron", 1000},
+	"potassium_100g":     {"potassium", 1000},
+}
+
+// offAllergens maps the allergen tags of Open Food Facts to the allergens.
+var offAllergens = map[string]string{
+	"en:gluten":       "gluten",
+	"en:nuts":         "nuts",
+	"en:peanuts":      "peanuts",
+	"en:milk":         "dairy",
+	"en:eggs":         "egg",
+	"en:soybeans":     "soy",
+	"en:fish":         "fish",
+	"en:crustaceans":  "shellfish",
+	"en:molluscs":     "shellfish",
+	"en:sesame-seeds": "sesame",
+}
+
+// offDiets maps the label tags of Open Food Facts to the diets.
+var offDiets = map[string]string{
+	"en:vegan":      "vegan",
+	"en:vegetarian": "vegetarian",
+	"en:halal":      "halal",
+	"en:kosher":     "kosher",
+	"en:keto":       "keto",
+}
+
+// offProduct defines struct of a product in the JSONL dump of Open Food Facts.
+type offProduct struct {
+	Code          string                 `json:"code"`
+	ProductName   string                 `json:"product_name"`
+	Nutriments    map[string]interface{} `json:"nutriments"`
+	AllergensTags []string               `json:"allergens_tags"`
+	LabelsTags    []string               `json:"labels_tags"`
+}
+
+// offAddFunc defines the function which adds a product of Open Food Facts with its nutriments and its tags.
+type offAddFunc func(line int, code string, name string, value func(key string) (float64, bool), allergens []string, labels []string)
+
+// usdaFood defines struct of a food in the JSON of USDA FoodData Central.
+type usdaFood struct {
+	FdcID         int                `json:"fdcId"`
+	Description   string             `json:"description"`
+	FoodNutrients []usdaFoodNutrient `json:"foodNutrients"`
+}
+
+// usdaFoodNutrient defines struct of a nutrient of a food in the JSON of USDA FoodData Central.
+type usdaFoodNutrient struct {
+	Nutrient struct {
+		Number string `json:"number"`
+	} `json:"nutrient"`
+	Amount float64 `json:"amount"`
+}
+
+// detectImportFormat returns the format of the file by its extension unless the format is given.
+func detectImportFormat(path string, format string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = ImportFormatCSV
+		case ".json":
+			format = ImportFormatUSDA
+		case ".jsonl":
+			format = ImportFormatOFF
+		}
+	}
+	if format != ImportFormatCSV && format != ImportFormatUSDA && format != ImportFormatOFF {
+		return "", errors.New("format must be csv, usda or off")
+	}
+	return format, nil
+}
+
+// parseFoodCSV reads the foods from the CSV file. The amounts are per 100 g.
+// The optional columns allergens and diets have the tags separated by semicolons, and the unknown tags are ignored.
+// The rows without the name or the calories are skipped.
+func parseFoodCSV(r io.Reader, micronutrients []string, result *model.ImportResult) ([]model.Food, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns[:2] {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("the column %s is required", name)
+		}
+	}
+
+	var foods []model.Food
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+
+		value := func(name string) (float64, bool) {
+			i, ok := index[name]
+			if !ok || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				return 0, false
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(record[i]), 64)
+			return v, err == nil
+		}
+
+		name := strings.TrimSpace(record[index["name"]])
+		calories, ok := value("calories")
+		if name == "" || !ok {
+			result.Skip("line %d: the name or the calories is missing", line)
+			continue
+		}
+
+		amounts := make([]float64, len(csvColumns))
+		for i, column := range csvColumns[2:] {
+			amounts[i+2], _ = value(column)
+		}
+		micro := make(map[string]float64)
+		for _, column := range micronutrients {
+			if v, ok := value(column); ok {
+				micro[column] = v
+			}
+		}
+
+		source_id := ""
+		if i, ok := index["source_id"]; ok && i < len(record) {
+			source_id = strings.TrimSpace(record[i])
+		}
+		tags := func(name string, known []string) []string {
+			i, ok := index[name]
+			if !ok || i >= len(record) {
+				return nil
+			}
+			return knownTags(strings.Split(record[i], ";"), known)
+		}
+		food := model.NewImportedFood(name, ImportFormatCSV, source_id,
+			model.NewNutrients(calories, amounts[2], amounts[3], amounts[4], amounts[5], amounts[6], amounts[7], micro))
+		foods = append(foods, *food.WithTags(tags("allergens", model.Allergens), tags("diets", model.Diets)))
+	}
+	return foods, nil
+}
+
+// parseFoodUSDA reads the foods from the JSON of USDA FoodData Central.
+// It accepts both an array of foods and an object such as {"FoundationFoods": [...]}.
+// The foods without the energy are skipped.
+func parseFoodUSDA(r io.Reader, micronutrients []string, result *model.ImportResult) ([]model.Food, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse the json: %w", err)
+	}
+
+	var items []usdaFood
+	if err := json.Unmarshal(raw, &items); err != nil {
+		var groups map[string][]usdaFood
+		if err := json.Unmarshal(raw, &groups); err != nil {
+			return nil, fmt.Errorf("failed to parse the json: %w", err)
+		}
+		for _, group := range groups {
+			items = append(items, group...)
+		}
+	}
+
+	tracked := make(map[string]bool)
+	for _, name := range micronutrients {
+		tracked[name] = true
+	}
+
+	var foods []model.Food
+	for _, item := range items {
+		numbers := make(map[string]float64)
+		for _, n := range item.FoodNutrients {
+			numbers[n.Nutrient.Number] = n.Amount
+		}
+
+		amounts := make(map[string]float64)
+		micro := make(map[string]float64)
+		for number, name := range usdaNutrients {
+			if amount, ok := numbers[number]; ok {
+				amounts[name] = amount
+				if tracked[name] {
+					micro[name] = amount
+				}
+			}
+		}
+
+		var calories float64
+		ok := false
+		for _, number := range usdaEnergies {
+			if calories, ok = numbers[number]; ok {
+				break
+			}
+		}
+		if item.Description == "" || !ok {
+			result.Skip("fdcId %d: the description or the energy is missing", item.FdcID)
+			continue
+		}
+		foods = append(foods, *model.NewImportedFood(item.Description, ImportFormatUSDA, strconv.Itoa(item.FdcID),
+			model.NewNutrients(calories, amounts["protein"], amounts["carbohydrate"], amounts["fat"],
+				amounts["fiber"], amounts["sugar"], amounts["sodium"], micro)))
+	}
+	return foods, nil
+}
+
+// parseFoodOFF reads the packaged foods from the dump of Open Food Facts.
+// The JSONL dump has a product per line and the CSV dump is separated by tabs or commas with a header row.
+// The allergen and the label tags are mapped to the allergens and the diets.
+// The products without a valid barcode, the name or the energy are skipped.
+func parseFoodOFF(r io.Reader, csvDump bool, micronutrients []string, result *model.ImportResult) ([]model.Food, error) {
+	tracked := make(map[string]bool)
+	for _, name := range micronutrients {
+		tracked[name] = true
+	}
+
+	var foods []model.Food
+	add := func(line int, code string, name string, value func(key string) (float64, bool), allergens []string, labels []string) {
+		barcode, err := util.NormalizeBarcode(code)
+		if err != nil {
+			result.Skip("line %d: %s", line, err.Error())
+			return
+		}
+		calories, ok := value("energy-kcal_100g")
+		if !ok {
+			if kj, found := value("energy_100g"); found {
+				calories, ok = kj/4.184, true
+			}
+		}
+		if strings.TrimSpace(name) == "" || !ok {
+			result.Skip("line %d: the product name or the energy is missing", line)
+			return
+		}
+
+		amounts := make(map[string]float64)
+		micro := make(map[string]float64)
+		for key, nutrient := range offNutrients {
+			if amount, ok := value(key); ok {
+				amounts[nutrient.name] = amount * nutrient.factor
+				if tracked[nutrient.name] {
+					micro[nutrient.name] = amount * nutrient.factor
+				}
+			}
+		}
+		food := model.NewPackagedFood(strings.TrimSpace(name), barcode, ImportFormatOFF, strings.TrimSpace(code),
+			model.NewNutrients(calories, amounts["protein"], amounts["carbohydrate"], amounts["fat"],
+				amounts["fiber"], amounts["sugar"], amounts["sodium"], micro))
+		foods = append(foods, *food.WithTags(mapTags(allergens, offAllergens), mapTags(labels, offDiets)))
+	}
+
+	if csvDump {
+		return foods, parseOFFCSV(r, add)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var product offProduct
+		if err := json.Unmarshal(scanner.Bytes(), &product); err != nil {
+			result.Skip("line %d: %s", line, err.Error())
+			continue
+		}
+		add(line, product.Code, product.ProductName, func(key string) (float64, bool) {
+			switch v := product.Nutriments[key].(type) {
+			case float64:
+				return v, true
+			case string:
+				f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				return f, err == nil
+			}
+			return 0, false
+		}, product.AllergensTags, product.LabelsTags)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the dump: %w", err)
+	}
+	return foods, nil
+}
+
+// parseOFFCSV reads the rows of the CSV dump of Open Food Facts and passes them to add.
+// The separator is detected from the header row because the official dump is separated by tabs.
+func parseOFFCSV(r io.Reader, add offAddFunc) error {
+	buffered := bufio.NewReader(r)
+	first, err := buffered.Peek(buffered.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return fmt.Errorf("failed to read the header: %w", err)
+	}
+
+	reader := csv.NewReader(buffered)
+	if header, _, _ := strings.Cut(string(first), "\n"); strings.Contains(header, "\t") {
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+	}
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read the header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"code", "product_name"} {
+		if _, ok := index[name]; !ok {
+			return fmt.Errorf("the column %s is required", name)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+
+		column := func(key string) string {
+			if i, ok := index[key]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		tags := func(keys ...string) []string {
+			for _, key := range keys {
+				if _, ok := index[key]; ok {
+					return strings.Split(column(key), ",")
+				}
+			}
+			return nil
+		}
+		add(line, column("code"), column("product_name"), func(key string) (float64, bool) {
+			v, err := strconv.ParseFloat(column(key), 64)
+			return v, err == nil
+		}, tags("allergens_tags", "allergens"), tags("labels_tags"))
+	}
+}
+
+// knownTags returns the trimmed tags in lower case which are in the known tags without duplicates.
+func knownTags(tags []string, known []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && containsAll(known, []string{tag}) && !containsAll(result, []string{tag}) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// mapTags returns the tags mapped by given mapping without duplicates. The tags without mapping are ignored.
+// It returns nil if the tags are nil, which means that they are unknown.
+func mapTags(tags []string, mapping map[string]string) []string {
+	if tags == nil {
+		return nil
+	}
+	result := []string{}
+	for _, tag := range tags {
+		if t, ok := mapping[strings.ToLower(strings.TrimSpace(tag))]; ok && !containsAll(result, []string{t}) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// readFoodFile reads the foods from the file on local disk in given format.
+func readFoodFile(path string, format string, micronutrients []string, result *model.ImportResult) ([]model.Food, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch format {
+	case ImportFormatCSV:
+		return parseFoodCSV(file, micronutrients, result)
+	case ImportFormatOFF:
+		ext := strings.ToLower(filepath.Ext(path))
+		return parseFoodOFF(file, ext == ".csv" || ext == ".tsv", micronutrients, result)
+	}
+	return parseFoodUSDA(file, micronutrients, result)
+}
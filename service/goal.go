@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// GoalService is a service for managing the goals of the logged-in user.
+type GoalService interface {
+	FindGoals() (*[]model.Goal, error)
+	UpdateGoal(dto *dto.GoalDto) (*model.Goal, map[string]string)
+	FindProgress(date string) (*model.GoalProgress, error)
+}
+
+type goalService struct {
+	container container.Container
+}
+
+// NewGoalService is constructor.
+func NewGoalService(container container.Container) GoalService {
+	return &goalService{container: container}
+}
+
+// FindGoals returns the history of the goals of the logged-in user.
+func (g *goalService) FindGoals() (*[]model.Goal, error) {
+	user := g.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := g.container.GetRepository()
+	goal := model.Goal{}
+	result, err := goal.FindByUser(rep, user)
+	if err != nil {
+		g.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateGoal registers a new goal of the logged-in user. The previous goals are kept as the history.
+func (g *goalService) UpdateGoal(dto *dto.GoalDto) (*model.Goal, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := g.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := g.container.GetRepository()
+	result, err := dto.Create(user, user.Location(rep)).Create(rep)
+	if err != nil {
+		g.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// FindProgress returns the progress of the logged-in user toward the goal which applies to a given day.
+func (g *goalService) FindProgress(date string) (*model.GoalProgress, error) {
+	user := g.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := g.container.GetRepository()
+	day, err := util.ParseDateIn(date, user.Location(rep))
+	if err != nil {
+		return nil, errors.New("failed to parse the date")
+	}
+
+	progress := model.GoalProgress{}
+	result, err := progress.FindByUserAndDate(rep, user, day)
+	if err != nil {
+		g.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// HydrationService is a service for managing the hydration log of the logged-in user.
+type HydrationService interface {
+	FindHydrationByPage(page string, size string) (*model.Page, error)
+	FindDailyHydration(date string) (*model.Hydration, error)
+	CreateHydration(dto *dto.HydrationDto) (*model.HydrationEntry, map[string]string)
+	DeleteHydration(id string) (*model.HydrationEntry, map[string]string)
+}
+
+type hydrationService struct {
+	container container.Container
+}
+
+// NewHydrationService is constructor.
+func NewHydrationService(container container.Container) HydrationService {
+	return &hydrationService{container: container}
+}
+
+// FindHydrationByPage returns the page object of the hydration log of the logged-in user.
+func (h *hydrationService) FindHydrationByPage(page string, size string) (*model.Page, error) {
+	user := h.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := h.container.GetRepository()
+	entry := model.HydrationEntry{}
+	result, err := entry.FindByUserAndPage(rep, user, page, size)
+	if err != nil {
+		h.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindDailyHydration returns the total volume and the target of the logged-in user in a given day.
+func (h *hydrationService) FindDailyHydration(date string) (*model.Hydration, error) {
+	user := h.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := h.container.GetRepository()
+	day, err := util.ParseDateIn(date, user.Location(rep))
+	if err != nil {
+		return nil, errors.New("failed to parse the date")
+	}
+
+	hydration := model.Hydration{}
+	result, err := hydration.FindByUserAndDate(rep, user, day)
+	if err != nil {
+		h.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateHydration register the given hydration data.
+func (h *hydrationService) CreateHydration(dto *dto.HydrationDto) (*model.HydrationEntry, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := h.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := h.container.GetRepository()
+	result, err := dto.Create(user).Create(rep)
+	if err != nil {
+		h.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// DeleteHydration deletes the given hydration data.
+func (h *hydrationService) DeleteHydration(id string) (*model.HydrationEntry, map[string]string) {
+	user := h.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := h.container.GetRepository()
+	entry := model.HydrationEntry{}
+	target, err := entry.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	result, err := target.Delete(rep)
+	if err != nil {
+		h.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
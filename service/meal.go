@@ -15,10 +15,19 @@ type MealService interface {
 	FindByID(id string) (*model.Meal, error)
 	FindAllMeals() (*[]model.Meal, error)
 	FindAllMealsByPage(page string, size string) (*model.Page, error)
-	FindMealsByName(meal_name string, page string, size string) (*model.Page, error)
+	FindMeals(dto *dto.MealFilterDto) (*model.Page, map[string]string)
 	CreateMeal(dto *dto.MealDto) (*model.Meal, map[string]string)
+	CopyMeals(from string, to string) (*[]model.Meal, map[string]string)
+	RecalculateMeal(id string) (*model.Meal, map[string]string)
+	UpdateMeal(dto *dto.MealDto, id string) (*model.Meal, map[string]string)
+	PatchMeal(patch []byte, id string) (*model.Meal, map[string]string)
+	DeleteMeal(id string) (*model.Meal, map[string]string)
+	BatchMeals(dto *dto.MealBatchDto) (*model.MealBatchResult, map[string]string)
 }
 
+// ErrMealNotFound is returned when no meal of the logged-in user is matched.
+var ErrMealNotFound = errors.New("meal not found")
+
 type mealService struct {
 	container container.Container
 }
@@ -28,18 +37,18 @@ func NewMealService(container container.Container) MealService {
 	return &mealService{container: container}
 }
 
-// FindByID returns one record matched meal's id.
+// FindByID returns one record matched meal's id if it belongs to the logged-in user.
+// The meals of the other users are reported as not found.
 func (m *mealService) FindByID(id string) (*model.Meal, error) {
 	if !util.IsNumeric(id) {
 		return nil, errors.New("failed to fetch data")
 	}
 
 	rep := m.container.GetRepository()
-	meal := model.Meal{}
-	var result *model.Meal
-	var err error
-	if result, err = meal.FindByID(rep, util.ConvertToUint(id)).Take(); err != nil {
-		return nil, err
+	user := m.container.GetSession().GetUser()
+	result, err := txFindOwnedMeal(rep, user, id)
+	if err != nil {
+		return nil, ErrMealNotFound
 	}
 	return result, nil
 }
@@ -68,56 +77,340 @@ func (m *mealService) FindAllMealsByPage(page string, size string) (*model.Page,
 	return result, nil
 }
 
-// FindMealsByTitle returns the page object of meals matched given meal title.
-func (m *mealService) FindMealsByName(meal_name string, page string, size string) (*model.Page, error) {
+// FindMeals returns the page object of the meals of the logged-in user matched the given filters in the given order.
+// The meals are paged by the cursor if the given dto has it, or by the page number otherwise.
+func (m *mealService) FindMeals(dto *dto.MealFilterDto) (*model.Page, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := m.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to fetch data"}
+	}
+
 	rep := m.container.GetRepository()
 	meal := model.Meal{}
-	result, err := meal.FindByName(rep, meal_name, page, size)
+	filter := dto.Create(user, user.Location(rep))
+
+	var result *model.Page
+	var err error
+	if dto.IsCursor() {
+		result, err = meal.FindByCursor(rep, filter, dto.Cursor(), dto.Size())
+	} else {
+		result, err = meal.FindByFilter(rep, filter, dto.Page(), dto.Size())
+	}
 	if err != nil {
 		m.container.GetLogger().GetZapLogger().Errorf(err.Error())
-		return nil, err
+		return nil, map[string]string{"error": "Failed to fetch data"}
 	}
 	return result, nil
 }
 
-// CreateMeal register the given meal data.
+// CreateMeal register the given meal data as a meal of the logged-in user.
 func (m *mealService) CreateMeal(dto *dto.MealDto) (*model.Meal, map[string]string) {
 	if errors := dto.Validate(); errors != nil {
 		return nil, errors
 	}
 
+	user := m.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
 	rep := m.container.GetRepository()
 	var result *model.Meal
 	var err error
 
 	if trerr := rep.Transaction(func(txrep repository.Repository) error {
-		result, err = txCreateMeal(txrep, dto)
+		result, err = txCreateMeal(txrep, user, dto)
 		return err
 	}); trerr != nil {
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
 		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
 		return nil, map[string]string{"error": "Failed to the registration"}
 	}
 	return result, nil
 }
 
-func txCreateMeal(txrep repository.Repository, dto *dto.MealDto) (*model.Meal, error) {
+func txCreateMeal(txrep repository.Repository, user *model.User, dto *dto.MealDto) (*model.Meal, error) {
 	var result *model.Meal
 	var err error
-	meal := dto.Create()
+	meal := dto.Create(user)
 
-	category := model.Category{}
-	if meal.Category, err = category.FindByID(txrep, dto.CategoryID).Take(); err != nil {
+	if result, err = meal.Create(txrep, user); err != nil {
 		return nil, err
 	}
 
-	format := model.Format{}
-	if meal.Format, err = format.FindByID(txrep, dto.FormatID).Take(); err != nil {
+	return result, nil
+}
+
+// CopyMeals duplicates all meals of the logged-in user on the day of from to the day of to at the same times.
+// If they are omitted, it copies yesterday's meals to today.
+func (m *mealService) CopyMeals(from string, to string) (*[]model.Meal, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	rep := m.container.GetRepository()
+	loc := user.Location(rep)
+	toDate, err := util.ParseDateIn(to, loc)
+	if err != nil {
+		return nil, map[string]string{"to": dto.ValidationErrMessageDate}
+	}
+	fromDate := toDate.AddDate(0, 0, -1)
+	if from != "" {
+		if fromDate, err = util.ParseDateIn(from, loc); err != nil {
+			return nil, map[string]string{"from": dto.ValidationErrMessageDate}
+		}
+	}
+
+	meal := model.Meal{}
+	var result []model.Meal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		meals, err := meal.FindByUserAndDate(txrep, user, fromDate)
+		if err != nil {
+			return err
+		}
+		copies := make([]model.Meal, len(meals))
+		for i := range meals {
+			copies[i] = *meals[i].CopyTo(toDate)
+		}
+		result, err = txCreateMeals(txrep, user, copies)
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return &result, nil
+}
+
+// RecalculateMeal replaces the snapshots of the nutrients of the meal of the logged-in user
+// with the current nutrients of the foods.
+func (m *mealService) RecalculateMeal(id string) (*model.Meal, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	var result *model.Meal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		meal, err := txFindOwnedMeal(txrep, user, id)
+		if err != nil {
+			return err
+		}
+		result, err = meal.Recalculate(txrep)
+		return err
+	}); trerr != nil {
+		if errors := dto.UnitErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// UpdateMeal replaces the given meal of the logged-in user with the given meal data.
+func (m *mealService) UpdateMeal(dto *dto.MealDto, id string) (*model.Meal, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := m.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	var result *model.Meal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		meal, err := txFindOwnedMeal(txrep, user, id)
+		if err != nil {
+			return err
+		}
+		result, err = meal.Update(txrep, user, dto.Create(user))
+		return err
+	}); trerr != nil {
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// PatchMeal applies the given JSON Merge Patch to the given meal of the logged-in user.
+// The items of the meal are kept unless the patch has the items or the recipes.
+func (m *mealService) PatchMeal(patch []byte, id string) (*model.Meal, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := m.container.GetRepository()
+	var result *model.Meal
+	var messages map[string]string
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		meal, err := txFindOwnedMeal(txrep, user, id)
+		if err != nil {
+			return err
+		}
+		data, err := dto.MergeMealDto(meal, patch)
+		if err != nil {
+			messages = map[string]string{"error": "The patch is not a valid JSON Merge Patch."}
+			return err
+		}
+		if messages = data.Validate(); messages != nil {
+			return errors.New("the patched meal is invalid")
+		}
+		result, err = meal.Update(txrep, user, data.Create(user))
+		return err
+	}); trerr != nil {
+		if messages != nil {
+			return nil, messages
+		}
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteMeal deletes the given meal of the logged-in user and its items.
+func (m *mealService) DeleteMeal(id string) (*model.Meal, map[string]string) {
+	user := m.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := m.container.GetRepository()
+	var result *model.Meal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		meal, err := txFindOwnedMeal(txrep, user, id)
+		if err != nil {
+			return err
+		}
+		result, err = meal.Delete(txrep)
+		return err
+	}); trerr != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
+
+// BatchMeals applies the given operations to the meals of the logged-in user in a transaction.
+// In the atomic mode, none of the operations is applied if any of them is invalid or fails.
+// In the best_effort mode, each operation is applied under its own save point, so that the failed ones are undone alone.
+func (m *mealService) BatchMeals(dto *dto.MealBatchDto) (*model.MealBatchResult, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := m.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	result := model.NewMealBatchResult(dto.Mode(), dto.Ops())
+	operations := dto.Operations()
+	for i := range operations {
+		if errors := operations[i].Validate(); errors != nil {
+			result.Fail(i, errors)
+		}
+	}
+	if result.IsAtomic() && result.HasFailures() {
+		return result, nil
+	}
+
+	rep := m.container.GetRepository()
+	if err := result.Apply(rep, func(txrep repository.Repository, i int) (*model.Meal, error) {
+		return txApplyMealOperation(txrep, user, &operations[i])
+	}, func(i int, err error) map[string]string {
+		return m.batchErrorMessages(operations[i].Op(), err)
+	}); err != nil {
+		m.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// txApplyMealOperation applies the given operation of a batch to the meals of the given user.
+// The created meals belong to the given user whatever the meal data says.
+func txApplyMealOperation(txrep repository.Repository, user *model.User, op *dto.MealBatchOperationDto) (*model.Meal, error) {
+	if op.Op() == model.MealBatchCreate {
+		return txCreateMeal(txrep, user, op.Meal())
+	}
+	meal, err := txFindOwnedMeal(txrep, user, op.ID())
+	if err != nil {
 		return nil, err
 	}
+	if op.Op() == model.MealBatchUpdate {
+		return meal.Update(txrep, user, op.Meal().Create(user))
+	}
+	return meal.Delete(txrep)
+}
+
+// batchErrorMessages returns the error messages of the failed operation of a batch.
+// The errors which the user cannot correct are logged and reported as the failure of the kind of the operation.
+func (m *mealService) batchErrorMessages(op string, err error) map[string]string {
+	if errors := registrationErrorMessages(err); errors != nil {
+		return errors
+	}
+	m.container.GetLogger().GetZapLogger().Errorf(err.Error())
+	switch op {
+	case model.MealBatchUpdate:
+		return map[string]string{"error": "Failed to the update"}
+	case model.MealBatchDelete:
+		return map[string]string{"error": "Failed to the delete"}
+	}
+	return map[string]string{"error": "Failed to the registration"}
+}
 
-	if result, err = meal.Create(txrep); err != nil {
+// txFindOwnedMeal returns the meal of the given ID if it belongs to the given user.
+func txFindOwnedMeal(txrep repository.Repository, user *model.User, id string) (*model.Meal, error) {
+	meal := model.Meal{}
+	result, err := meal.FindByID(txrep, util.ConvertToUint(id)).Take()
+	if err != nil {
 		return nil, err
 	}
+	if !result.IsOwnedBy(user) {
+		return nil, model.ErrMealNotOwned
+	}
+	return result, nil
+}
+
+// registrationErrorMessages returns the error messages of the meal which the user can correct,
+// such as the conflicts with the restrictions or the unit which cannot be converted. Otherwise it returns nil.
+func registrationErrorMessages(err error) map[string]string {
+	var rerr *model.RestrictionError
+	if errors.As(err, &rerr) {
+		return map[string]string{"restrictions": rerr.Error()}
+	}
+	return dto.UnitErrorMessages(err)
+}
 
+// txCreateMeals persists given meals of the given user in the transaction.
+func txCreateMeals(txrep repository.Repository, user *model.User, meals []model.Meal) ([]model.Meal, error) {
+	result := make([]model.Meal, 0, len(meals))
+	for i := range meals {
+		created, err := meals[i].Create(txrep, user)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *created)
+	}
 	return result, nil
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+const (
+	// defaultPlanDays is the number of days of the meal plan calendar when the end is omitted.
+	defaultPlanDays = 7
+	// maxPlanDays is the maximum number of days of the meal plan calendar.
+	maxPlanDays = 92
+)
+
+// MealPlanService is a service for planning the meals of the logged-in user.
+type MealPlanService interface {
+	FindCalendar(from string, to string) (*[]model.MealPlanDay, error)
+	CreatePlan(dto *dto.PlannedMealDto) (*model.PlannedMeal, map[string]string)
+	EatPlan(id string) (*model.Meal, map[string]string)
+	SkipPlan(id string) (*model.PlannedMeal, map[string]string)
+	DeletePlan(id string) (*model.PlannedMeal, map[string]string)
+}
+
+type mealPlanService struct {
+	container container.Container
+}
+
+// NewMealPlanService is constructor.
+func NewMealPlanService(container container.Container) MealPlanService {
+	return &mealPlanService{container: container}
+}
+
+// FindCalendar returns the planned and the logged meals of the logged-in user for each day from a day to a day.
+// The range defaults to 7 days from today.
+func (p *mealPlanService) FindCalendar(from string, to string) (*[]model.MealPlanDay, error) {
+	user := p.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := p.container.GetRepository()
+	loc := user.Location(rep)
+	start, err := util.ParseDateIn(from, loc)
+	if err != nil {
+		return nil, errors.New("failed to parse the date")
+	}
+	end := start.AddDate(0, 0, defaultPlanDays-1)
+	if to != "" {
+		if end, err = util.ParseDateIn(to, loc); err != nil {
+			return nil, errors.New("failed to parse the date")
+		}
+	}
+	if start.After(end) {
+		return nil, errors.New("from must be before to")
+	}
+	if end.After(start.AddDate(0, 0, maxPlanDays-1)) {
+		return nil, errors.New("the range must be within 92 days")
+	}
+
+	day := model.MealPlanDay{}
+	result, err := day.FindByUserAndRange(rep, user, start, end)
+	if err != nil {
+		p.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreatePlan schedules the given meal for the logged-in user.
+func (p *mealPlanService) CreatePlan(dto *dto.PlannedMealDto) (*model.PlannedMeal, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := p.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := p.container.GetRepository()
+	var result *model.PlannedMeal
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user).Create(txrep)
+		return err
+	}); trerr != nil {
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		p.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// EatPlan logs the given planned meal as a meal at the planned time and marks it as eaten.
+func (p *mealPlanService) EatPlan(id string) (*model.Meal, map[string]string) {
+	plan, err := p.findByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	user := p.container.GetSession().GetUser()
+	rep := p.container.GetRepository()
+	var result *model.Meal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = plan.Eat(txrep, user)
+		return err
+	}); trerr != nil {
+		p.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": trerr.Error()}
+	}
+	return result, nil
+}
+
+// SkipPlan marks the given planned meal as skipped.
+func (p *mealPlanService) SkipPlan(id string) (*model.PlannedMeal, map[string]string) {
+	plan, err := p.findByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := p.container.GetRepository()
+	result, err := plan.Skip(rep)
+	if err != nil {
+		p.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": err.Error()}
+	}
+	return result, nil
+}
+
+// DeletePlan deletes the given planned meal. The meal logged from it is not changed.
+func (p *mealPlanService) DeletePlan(id string) (*model.PlannedMeal, map[string]string) {
+	plan, err := p.findByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := p.container.GetRepository()
+	var result *model.PlannedMeal
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = plan.Delete(txrep)
+		return err
+	}); trerr != nil {
+		p.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
+
+// findByID returns one planned meal of the logged-in user matched given id.
+func (p *mealPlanService) findByID(id string) (*model.PlannedMeal, error) {
+	user := p.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := p.container.GetRepository()
+	plan := model.PlannedMeal{}
+	return plan.FindByID(rep, user, util.ConvertToUint(id)).Take()
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// RecipeService is a service for managing the recipes of the logged-in user.
+type RecipeService interface {
+	FindByID(id string, version string, servings string) (*model.Recipe, error)
+	FindAllRecipes() (*[]model.Recipe, error)
+	CreateRecipe(dto *dto.RecipeDto) (*model.Recipe, map[string]string)
+	UpdateRecipe(dto *dto.RecipeDto, id string) (*model.Recipe, map[string]string)
+}
+
+type recipeService struct {
+	container container.Container
+}
+
+// NewRecipeService is constructor.
+func NewRecipeService(container container.Container) RecipeService {
+	return &recipeService{container: container}
+}
+
+// FindByID returns one recipe of the logged-in user matched recipe's id.
+// If version is omitted, it returns the current version.
+// If servings is given, the quantities and the nutrients are scaled to the servings.
+func (r *recipeService) FindByID(id string, version string, servings string) (*model.Recipe, error) {
+	user := r.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) || (version != "" && !util.IsNumeric(version)) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := r.container.GetRepository()
+	recipe := model.Recipe{}
+	result, err := recipe.FindByID(rep, user, util.ConvertToUint(id), util.ConvertToInt(version)).Take()
+	if err != nil {
+		return nil, err
+	}
+
+	if servings != "" {
+		s, err := strconv.ParseFloat(servings, 64)
+		if err != nil || s <= 0 {
+			return nil, errors.New("servings must be greater than 0")
+		}
+		result = result.Scale(s)
+	}
+	return result, nil
+}
+
+// FindAllRecipes returns the current versions of all recipes of the logged-in user.
+func (r *recipeService) FindAllRecipes() (*[]model.Recipe, error) {
+	user := r.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := r.container.GetRepository()
+	recipe := model.Recipe{}
+	result, err := recipe.FindByUser(rep, user)
+	if err != nil {
+		r.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateRecipe register the given recipe data.
+func (r *recipeService) CreateRecipe(dto *dto.RecipeDto) (*model.Recipe, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := r.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := r.container.GetRepository()
+	var result *model.Recipe
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user).Create(txrep)
+		return err
+	}); trerr != nil {
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		r.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// UpdateRecipe adds a new version of the given recipe. The meals logged from the former versions are not changed.
+func (r *recipeService) UpdateRecipe(dto *dto.RecipeDto, id string) (*model.Recipe, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := r.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := r.container.GetRepository()
+	var result *model.Recipe
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		recipe := model.Recipe{}
+		found, err := recipe.FindByID(txrep, user, util.ConvertToUint(id), 0).Take()
+		if err != nil {
+			return err
+		}
+		result, err = found.Update(txrep, dto.Create(user))
+		return err
+	}); trerr != nil {
+		if errors.Is(trerr, model.ErrRecipeConflict) {
+			return nil, map[string]string{"error": "The recipe has been updated by another request. Please reload it and try again."}
+		}
+		if errors := registrationErrorMessages(trerr); errors != nil {
+			return nil, errors
+		}
+		r.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// defaultTrendDays is the number of days of a trend report when the range is omitted.
+const defaultTrendDays = 30
+
+// ReportService is a service for reporting the nutrition of the logged-in user.
+type ReportService interface {
+	FindTrends(from string, to string, granularity string) (*model.TrendReport, error)
+}
+
+type reportService struct {
+	container container.Container
+}
+
+// NewReportService is constructor.
+func NewReportService(container container.Container) ReportService {
+	return &reportService{container: container}
+}
+
+// FindTrends returns the calories and the macronutrients of the logged-in user from a day to a day
+// aggregated by day, week or month. The range defaults to the last 30 days up to today.
+func (r *reportService) FindTrends(from string, to string, granularity string) (*model.TrendReport, error) {
+	user := r.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	if granularity == "" {
+		granularity = repository.DAY
+	}
+	if granularity != repository.DAY && granularity != repository.WEEK && granularity != repository.MONTH {
+		return nil, errors.New("granularity must be day, week or month")
+	}
+
+	rep := r.container.GetRepository()
+	loc := user.Location(rep)
+	end, err := util.ParseDateIn(to, loc)
+	if err != nil {
+		return nil, errors.New("failed to parse the date")
+	}
+	start := end.AddDate(0, 0, 1-defaultTrendDays)
+	if from != "" {
+		if start, err = util.ParseDateIn(from, loc); err != nil {
+			return nil, errors.New("failed to parse the date")
+		}
+	}
+	if start.After(end) {
+		return nil, errors.New("from must be before to")
+	}
+
+	report := model.TrendReport{}
+	result, err := report.FindByUserAndRange(rep, user, start, end, granularity)
+	if err != nil {
+		r.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// ShoppingListService is a service for managing the shopping lists of the logged-in user.
+type ShoppingListService interface {
+	FindByID(id string) (*model.ShoppingList, error)
+	FindAllShoppingLists() (*[]model.ShoppingList, error)
+	CreateShoppingList(dto *dto.ShoppingListDto) (*model.ShoppingList, map[string]string)
+	CheckItem(dto *dto.ShoppingListItemDto, id string, item_id string) (*model.ShoppingListItem, map[string]string)
+	DeleteShoppingList(id string) (*model.ShoppingList, map[string]string)
+}
+
+type shoppingListService struct {
+	container container.Container
+}
+
+// NewShoppingListService is constructor.
+func NewShoppingListService(container container.Container) ShoppingListService {
+	return &shoppingListService{container: container}
+}
+
+// FindByID returns one shopping list of the logged-in user matched list's id with its items.
+func (s *shoppingListService) FindByID(id string) (*model.ShoppingList, error) {
+	user := s.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := s.container.GetRepository()
+	list := model.ShoppingList{}
+	result, err := list.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAllShoppingLists returns the list of all shopping lists of the logged-in user without the items.
+func (s *shoppingListService) FindAllShoppingLists() (*[]model.ShoppingList, error) {
+	user := s.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := s.container.GetRepository()
+	list := model.ShoppingList{}
+	result, err := list.FindByUser(rep, user)
+	if err != nil {
+		s.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateShoppingList generates a shopping list from the planned meals in the given range and the given recipes.
+func (s *shoppingListService) CreateShoppingList(dto *dto.ShoppingListDto) (*model.ShoppingList, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := s.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := s.container.GetRepository()
+	var result *model.ShoppingList
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user, user.Location(txrep)).Create(txrep, dto.Recipes())
+		return err
+	}); trerr != nil {
+		s.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// CheckItem marks or unmarks the given item of the given shopping list as bought.
+func (s *shoppingListService) CheckItem(dto *dto.ShoppingListItemDto, id string, item_id string) (*model.ShoppingListItem, map[string]string) {
+	list, err := s.FindByID(id)
+	if err != nil || !util.IsNumeric(item_id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := s.container.GetRepository()
+	result, err := list.Check(rep, util.ConvertToUint(item_id), dto.Checked())
+	if err != nil {
+		s.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteShoppingList deletes the given shopping list.
+func (s *shoppingListService) DeleteShoppingList(id string) (*model.ShoppingList, map[string]string) {
+	list, err := s.FindByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := s.container.GetRepository()
+	var result *model.ShoppingList
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = list.Delete(txrep)
+		return err
+	}); trerr != nil {
+		s.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// SummaryService is a service for summarizing the meals of the logged-in user.
+type SummaryService interface {
+	FindDailySummary(date string) (*model.DailySummary, error)
+}
+
+type summaryService struct {
+	container container.Container
+}
+
+// NewSummaryService is constructor.
+func NewSummaryService(container container.Container) SummaryService {
+	return &summaryService{container: container}
+}
+
+// FindDailySummary returns the total calories, the number of meals and the calories of each meal
+// which the logged-in user has taken in a given day.
+func (s *summaryService) FindDailySummary(date string) (*model.DailySummary, error) {
+	user := s.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := s.container.GetRepository()
+	day, err := util.ParseDateIn(date, user.Location(rep))
+	if err != nil {
+		return nil, errors.New("failed to parse the date")
+	}
+
+	summary := model.DailySummary{}
+	result, err := summary.FindByUserAndDate(rep, user, day)
+	if err != nil {
+		s.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// MealTemplateService is a service for managing the meal templates of the logged-in user.
+type MealTemplateService interface {
+	FindAllTemplates() (*[]model.MealTemplate, error)
+	CreateTemplate(dto *dto.MealTemplateDto) (*model.MealTemplate, map[string]string)
+	LogTemplate(id string, date string) (*[]model.Meal, map[string]string)
+	SetFavorite(id string, favorite bool) (*model.MealTemplate, map[string]string)
+	DeleteTemplate(id string) (*model.MealTemplate, map[string]string)
+}
+
+type mealTemplateService struct {
+	container container.Container
+}
+
+// NewMealTemplateService is constructor.
+func NewMealTemplateService(container container.Container) MealTemplateService {
+	return &mealTemplateService{container: container}
+}
+
+// FindAllTemplates returns the list of all meal templates of the logged-in user, the favorites first.
+func (t *mealTemplateService) FindAllTemplates() (*[]model.MealTemplate, error) {
+	user := t.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := t.container.GetRepository()
+	template := model.MealTemplate{}
+	result, err := template.FindByUser(rep, user)
+	if err != nil {
+		t.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateTemplate saves the given meal, or all meals of the given day, of the logged-in user as a template.
+func (t *mealTemplateService) CreateTemplate(dto *dto.MealTemplateDto) (*model.MealTemplate, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := t.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := t.container.GetRepository()
+	meal := model.Meal{}
+	var meals []model.Meal
+	if dto.MealID() != 0 {
+		m, err := meal.FindByID(rep, dto.MealID()).Take()
+		if err != nil || !m.IsOwnedBy(user) {
+			return nil, map[string]string{"meal_id": "The meal is not found."}
+		}
+		meals = []model.Meal{*m}
+	} else {
+		var err error
+		if meals, err = meal.FindByUserAndDate(rep, user, dto.Date(user.Location(rep))); err != nil {
+			t.container.GetLogger().GetZapLogger().Errorf(err.Error())
+			return nil, map[string]string{"error": "Failed to the registration"}
+		}
+		if len(meals) == 0 {
+			return nil, map[string]string{"date": "No meal is logged on the date."}
+		}
+	}
+
+	var result *model.MealTemplate
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = dto.Create(user).Create(txrep, meals)
+		return err
+	}); trerr != nil {
+		t.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// LogTemplate logs the meals of the given template on the given day, or today if it is omitted.
+func (t *mealTemplateService) LogTemplate(id string, date string) (*[]model.Meal, map[string]string) {
+	user := t.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	rep := t.container.GetRepository()
+	day, err := util.ParseDateIn(date, user.Location(rep))
+	if err != nil {
+		return nil, map[string]string{"date": dto.ValidationErrMessageDate}
+	}
+
+	template := model.MealTemplate{}
+	found, err := template.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, map[string]string{"error": "The template is not found."}
+	}
+
+	var result []model.Meal
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = txCreateMeals(txrep, user, found.Meals(day))
+		return err
+	}); trerr != nil {
+		t.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return &result, nil
+}
+
+// SetFavorite marks or unmarks the given template as a favorite.
+func (t *mealTemplateService) SetFavorite(id string, favorite bool) (*model.MealTemplate, map[string]string) {
+	user := t.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := t.container.GetRepository()
+	template := model.MealTemplate{}
+	found, err := template.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	result, err := found.SetFavorite(rep, favorite)
+	if err != nil {
+		t.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteTemplate deletes the given template. The meals logged from it are not changed.
+func (t *mealTemplateService) DeleteTemplate(id string) (*model.MealTemplate, map[string]string) {
+	user := t.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := t.container.GetRepository()
+	template := model.MealTemplate{}
+	found, err := template.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	var result *model.MealTemplate
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = found.Delete(txrep)
+		return err
+	}); trerr != nil {
+		t.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
@@ -1,14 +1,22 @@
 package service
 
 import (
+	"errors"
+
 	"github.com/ybkuroki/go-webapp-sample/container"
 	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserService is a service for managing user user.
 type UserService interface {
 	AuthenticateByUsernameAndPassword(username string, password string) (bool, *model.User)
+	FindProfile() (*model.Profile, error)
+	UpdateProfile(dto *dto.ProfileDto) (*model.Profile, map[string]string)
+	FindRestrictions() (*model.Restrictions, error)
+	UpdateRestrictions(dto *dto.RestrictionDto) (*model.Restrictions, map[string]string)
 }
 
 type userService struct {
@@ -38,3 +46,86 @@ func (a *userService) AuthenticateByUsernameAndPassword(username string, passwor
 
 	return true, result
 }
+
+// FindProfile returns the body profile of the logged-in user with BMI, BMR and TDEE.
+func (a *userService) FindProfile() (*model.Profile, error) {
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := a.container.GetRepository()
+	profile := model.Profile{}
+	result, err := profile.FindByUser(rep, user)
+	if err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateProfile updates the body profile and the time zone of the logged-in user.
+func (a *userService) UpdateProfile(dto *dto.ProfileDto) (*model.Profile, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := a.container.GetRepository()
+	if _, err := user.UpdateProfile(rep, dto.Height(), dto.BirthDate(), dto.Sex(), dto.ActivityLevel(), dto.Timezone()); err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	profile := model.Profile{}
+	result, err := profile.FindByUser(rep, user)
+	if err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// FindRestrictions returns the allergens and the diets of the logged-in user.
+func (a *userService) FindRestrictions() (*model.Restrictions, error) {
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := a.container.GetRepository()
+	if err := user.LoadRestrictions(rep); err != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return user.Restrictions(), nil
+}
+
+// UpdateRestrictions replaces the allergens and the diets of the logged-in user.
+func (a *userService) UpdateRestrictions(dto *dto.RestrictionDto) (*model.Restrictions, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := a.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := a.container.GetRepository()
+	var result *model.User
+	var err error
+
+	if trerr := rep.Transaction(func(txrep repository.Repository) error {
+		result, err = user.UpdateRestrictions(txrep, dto.Allergens(), dto.Diets(), dto.Mode())
+		return err
+	}); trerr != nil {
+		a.container.GetLogger().GetZapLogger().Errorf(trerr.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result.Restrictions(), nil
+}
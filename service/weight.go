@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/ybkuroki/go-webapp-sample/container"
+	"github.com/ybkuroki/go-webapp-sample/model"
+	"github.com/ybkuroki/go-webapp-sample/model/dto"
+	"github.com/ybkuroki/go-webapp-sample/util"
+)
+
+// WeightService is a service for managing the body weights of the logged-in user.
+type WeightService interface {
+	FindByID(id string) (*model.WeightEntry, error)
+	FindAllWeights() (*[]model.WeightEntry, error)
+	CreateWeight(dto *dto.WeightDto) (*model.WeightEntry, map[string]string)
+	UpdateWeight(dto *dto.WeightDto, id string) (*model.WeightEntry, map[string]string)
+	DeleteWeight(id string) (*model.WeightEntry, map[string]string)
+}
+
+type weightService struct {
+	container container.Container
+}
+
+// NewWeightService is constructor.
+func NewWeightService(container container.Container) WeightService {
+	return &weightService{container: container}
+}
+
+// FindByID returns one record of the logged-in user matched weight's id.
+func (w *weightService) FindByID(id string) (*model.WeightEntry, error) {
+	user := w.container.GetSession().GetUser()
+	if user == nil || !util.IsNumeric(id) {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := w.container.GetRepository()
+	entry := model.WeightEntry{}
+	result, err := entry.FindByID(rep, user, util.ConvertToUint(id)).Take()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAllWeights returns the list of all weights of the logged-in user.
+func (w *weightService) FindAllWeights() (*[]model.WeightEntry, error) {
+	user := w.container.GetSession().GetUser()
+	if user == nil {
+		return nil, errors.New("failed to fetch data")
+	}
+
+	rep := w.container.GetRepository()
+	entry := model.WeightEntry{}
+	result, err := entry.FindByUser(rep, user)
+	if err != nil {
+		w.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateWeight register the given weight data.
+func (w *weightService) CreateWeight(dto *dto.WeightDto) (*model.WeightEntry, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	user := w.container.GetSession().GetUser()
+	if user == nil {
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+
+	rep := w.container.GetRepository()
+	result, err := dto.Create(user).Create(rep)
+	if err != nil {
+		w.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the registration"}
+	}
+	return result, nil
+}
+
+// UpdateWeight updates the given weight data.
+func (w *weightService) UpdateWeight(dto *dto.WeightDto, id string) (*model.WeightEntry, map[string]string) {
+	if errors := dto.Validate(); errors != nil {
+		return nil, errors
+	}
+
+	entry, err := w.FindByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+
+	rep := w.container.GetRepository()
+	result, err := entry.Update(rep, dto.Weight(), dto.MeasuredAt())
+	if err != nil {
+		w.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the update"}
+	}
+	return result, nil
+}
+
+// DeleteWeight deletes the given weight data.
+func (w *weightService) DeleteWeight(id string) (*model.WeightEntry, map[string]string) {
+	entry, err := w.FindByID(id)
+	if err != nil {
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+
+	rep := w.container.GetRepository()
+	result, err := entry.Delete(rep)
+	if err != nil {
+		w.container.GetLogger().GetZapLogger().Errorf(err.Error())
+		return nil, map[string]string{"error": "Failed to the delete"}
+	}
+	return result, nil
+}
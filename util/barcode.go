@@ -0,0 +1,37 @@
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+// NormalizeBarcode validates given EAN-13 or UPC-A barcode by its check digit and returns it as EAN-13.
+// A UPC-A barcode is converted to EAN-13 by prefixing 0, so both forms of a product find the same food.
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == 12 {
+		code = "0" + code
+	}
+	if len(code) != 13 {
+		return "", errors.New("barcode must be EAN-13 or UPC-A")
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return "", errors.New("barcode must consist of digits")
+		}
+		digit := int(code[i] - '0')
+		if i == len(code)-1 {
+			if (10-sum%10)%10 != digit {
+				return "", errors.New("barcode has an invalid check digit")
+			}
+			break
+		}
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return code, nil
+}
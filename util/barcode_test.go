@@ -0,0 +1,45 @@
+package util
+
+import "testing"
+
+func TestNormalizeBarcodeReturnsEAN13(t *testing.T) {
+	valid := map[string]string{
+		"4006381333931":       "4006381333931",
+		"5901234123457":       "5901234123457",
+		"036000291452":        "0036000291452",
+		"0036000291452":       "0036000291452",
+		" 4006381333931\n":    "4006381333931",
+		"\t036000291452 \r\n": "0036000291452",
+	}
+	for code, want := range valid {
+		got, err := NormalizeBarcode(code)
+		if err != nil || got != want {
+			t.Errorf("NormalizeBarcode(%q) = %q, %v, want %q", code, got, err, want)
+		}
+	}
+}
+
+// A check digit catches every single wrong digit, so no barcode one digit away from a valid one is accepted.
+func TestNormalizeBarcodeRejectsEverySingleDigitError(t *testing.T) {
+	for _, code := range []string{"4006381333931", "036000291452"} {
+		for i := 0; i < len(code); i++ {
+			for d := byte('0'); d <= '9'; d++ {
+				if code[i] == d {
+					continue
+				}
+				wrong := code[:i] + string(d) + code[i+1:]
+				if got, err := NormalizeBarcode(wrong); err == nil {
+					t.Errorf("NormalizeBarcode(%q) = %q, want an error for the digit %d of %q", wrong, got, i, code)
+				}
+			}
+		}
+	}
+}
+
+func TestNormalizeBarcodeRejectsMalformedCodes(t *testing.T) {
+	for _, code := range []string{"", "40063813339", "40063813339310", "40063813339A1", "4006383133931", "4006-381333931"} {
+		if _, err := NormalizeBarcode(code); err == nil {
+			t.Errorf("NormalizeBarcode(%q) error = nil, want an error", code)
+		}
+	}
+}
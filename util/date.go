@@ -0,0 +1,51 @@
+package util
+
+import "time"
+
+// DateLayout is the layout of date parameters such as "2006-01-02".
+const DateLayout = "2006-01-02"
+
+// DefaultTimezone is the time zone of the users who have not configured their time zone.
+const DefaultTimezone = "UTC"
+
+// ParseDate parses given string as a date in the local time zone.
+// If the given string is empty, it returns today.
+func ParseDate(date string) (time.Time, error) {
+	return ParseDateIn(date, time.Local)
+}
+
+// ParseDateIn parses given string as a date in given time zone.
+// If the given string is empty, it returns today in the time zone.
+func ParseDateIn(date string, loc *time.Location) (time.Time, error) {
+	if date == "" {
+		return StartOfDay(time.Now().In(loc)), nil
+	}
+	return time.ParseInLocation(DateLayout, date, loc)
+}
+
+// StartOfDay returns the midnight of the day of given time in its time zone.
+// The next day starts at StartOfDay(t).AddDate(0, 0, 1), which is 23 or 25 hours later on the days of DST transitions.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// IsTimezone returns true if given name is an IANA time zone such as "Asia/Tokyo".
+func IsTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// LoadLocation returns the time zone of given IANA name.
+// It returns the DefaultTimezone if the name is empty or unknown.
+func LoadLocation(name string) *time.Location {
+	if IsTimezone(name) {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	loc, _ := time.LoadLocation(DefaultTimezone)
+	return loc
+}
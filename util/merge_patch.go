@@ -0,0 +1,51 @@
+package util
+
+import "encoding/json"
+
+// MergePatch applies given JSON Merge Patch (RFC 7386) to given JSON document.
+// The members of the patch replace those of the document and the members whose value is null are removed.
+// The objects are merged recursively, while the arrays and the other values are replaced as a whole.
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(target, p))
+}
+
+// PatchHasMember returns true if given JSON Merge Patch has any of given members at the top level.
+func PatchHasMember(patch []byte, names ...string) bool {
+	var p map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return false
+	}
+	for _, name := range names {
+		if _, ok := p[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		t[name] = mergeValue(t[name], value)
+	}
+	return t
+}
@@ -0,0 +1,67 @@
+package util
+
+import "testing"
+
+// TestMergePatchOfMeal applies the patches one after another to a meal, as PATCH /api/meals/:id does.
+// The objects are marshaled with sorted keys, so the documents can be compared as strings.
+func TestMergePatchOfMeal(t *testing.T) {
+	doc := []byte(`{"meal_name":"Lunch","meal_at":"2024-03-01T12:00:00Z","items":[{"food_id":1,"quantity":100,"unit":"g"}]}`)
+
+	steps := []struct {
+		patch string
+		want  string
+	}{
+		{`{"meal_name":"Late Lunch"}`,
+			`{"items":[{"food_id":1,"quantity":100,"unit":"g"}],"meal_at":"2024-03-01T12:00:00Z","meal_name":"Late Lunch"}`},
+		// The items are replaced as a whole rather than merged by their index.
+		{`{"items":[{"food_id":2,"quantity":1,"unit":"serving"}]}`,
+			`{"items":[{"food_id":2,"quantity":1,"unit":"serving"}],"meal_at":"2024-03-01T12:00:00Z","meal_name":"Late Lunch"}`},
+		{`{"meal_at":null,"note":{"mood":"tired","place":null}}`,
+			`{"items":[{"food_id":2,"quantity":1,"unit":"serving"}],"meal_name":"Late Lunch","note":{"mood":"tired"}}`},
+		{`{"note":{"mood":null,"place":"office"}}`,
+			`{"items":[{"food_id":2,"quantity":1,"unit":"serving"}],"meal_name":"Late Lunch","note":{"place":"office"}}`},
+		{`{}`,
+			`{"items":[{"food_id":2,"quantity":1,"unit":"serving"}],"meal_name":"Late Lunch","note":{"place":"office"}}`},
+	}
+	for i, step := range steps {
+		got, err := MergePatch(doc, []byte(step.patch))
+		if err != nil {
+			t.Fatalf("step %d: MergePatch(%s) error = %v", i, step.patch, err)
+		}
+		if string(got) != step.want {
+			t.Fatalf("step %d: MergePatch(%s) = %s, want %s", i, step.patch, got, step.want)
+		}
+		doc = got
+	}
+
+	// A patch which is not an object replaces the whole document.
+	if got, _ := MergePatch(doc, []byte(`["c"]`)); string(got) != `["c"]` {
+		t.Errorf("MergePatch() with an array = %s, want [\"c\"]", got)
+	}
+	if _, err := MergePatch(doc, []byte(`{"meal_name":`)); err == nil {
+		t.Error("MergePatch() with an invalid patch error = nil, want an error")
+	}
+	if _, err := MergePatch([]byte(`{"meal_name":`), []byte(`{}`)); err == nil {
+		t.Error("MergePatch() of an invalid document error = nil, want an error")
+	}
+}
+
+func TestPatchHasMember(t *testing.T) {
+	patch := []byte(`{"meal_name":"Dinner","items":null,"note":{"meal_at":"x"}}`)
+
+	if !PatchHasMember(patch, "meal_name") {
+		t.Error("PatchHasMember(meal_name) = false, want true")
+	}
+	if !PatchHasMember(patch, "meal_at", "items") {
+		t.Error("PatchHasMember(meal_at, items) = false, want true for the member set to null")
+	}
+	if PatchHasMember(patch, "meal_at") {
+		t.Error("PatchHasMember(meal_at) = true, want false for a nested member")
+	}
+	if PatchHasMember(patch) {
+		t.Error("PatchHasMember() = true, want false without names")
+	}
+	if PatchHasMember([]byte(`["meal_name"]`), "meal_name") || PatchHasMember([]byte(`{"meal_name":`), "meal_name") {
+		t.Error("PatchHasMember() = true, want false for a patch which is not an object")
+	}
+}
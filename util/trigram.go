@@ -0,0 +1,94 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// trigramThreshold is the minimum ratio of the query's trigrams which a text must share to be matched.
+const trigramThreshold = 0.3
+
+// TrigramIndex is an in-memory index which finds the texts similar to a query by their trigrams.
+// It tolerates typos because a misspelt word still shares most of its trigrams with the right one.
+// It is not safe for concurrent use.
+type TrigramIndex struct {
+	texts    map[uint]string
+	postings map[string][]uint
+}
+
+// TrigramMatch is an ID of the text matched a query and its score. The higher score is the more relevant.
+type TrigramMatch struct {
+	ID    uint
+	Score float64
+}
+
+// NewTrigramIndex is constructor.
+func NewTrigramIndex() *TrigramIndex {
+	return &TrigramIndex{texts: make(map[uint]string), postings: make(map[string][]uint)}
+}
+
+// Add indexes given text by given ID.
+func (x *TrigramIndex) Add(id uint, text string) {
+	x.texts[id] = strings.ToLower(text)
+	for _, gram := range trigrams(text) {
+		x.postings[gram] = append(x.postings[gram], id)
+	}
+}
+
+// Search returns the texts similar to given query in order of relevance.
+// The score is the ratio of the query's trigrams which the text has, plus 1 if the text starts with the query.
+func (x *TrigramIndex) Search(query string) []TrigramMatch {
+	grams := trigrams(query)
+	if len(grams) == 0 {
+		return []TrigramMatch{}
+	}
+
+	shared := make(map[uint]int)
+	for _, gram := range grams {
+		for _, id := range x.postings[gram] {
+			shared[id]++
+		}
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(query))
+	matches := []TrigramMatch{}
+	for id, count := range shared {
+		score := float64(count) / float64(len(grams))
+		if score < trigramThreshold {
+			continue
+		}
+		if strings.HasPrefix(x.texts[id], prefix) {
+			score++
+		}
+		matches = append(matches, TrigramMatch{ID: id, Score: score})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if len(x.texts[matches[i].ID]) != len(x.texts[matches[j].ID]) {
+			return len(x.texts[matches[i].ID]) < len(x.texts[matches[j].ID])
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches
+}
+
+// trigrams returns the unique trigrams of the words in given text.
+// Each word is padded with two spaces before and one after like pg_trgm, so that the beginning of a word weighs more.
+func trigrams(text string) []string {
+	seen := make(map[string]bool)
+	var grams []string
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			gram := string(runes[i : i+3])
+			if !seen[gram] {
+				seen[gram] = true
+				grams = append(grams, gram)
+			}
+		}
+	}
+	return grams
+}
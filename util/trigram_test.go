@@ -0,0 +1,73 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrigrams(t *testing.T) {
+	got := trigrams("Oat  OAT oats")
+	want := []string{"  o", " oa", "oat", "at ", "ats", "ts "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trigrams() = %q, want %q", got, want)
+	}
+	if got := trigrams(" \t "); len(got) != 0 {
+		t.Errorf("trigrams() of blanks = %q, want none", got)
+	}
+}
+
+func TestTrigramIndexToleratesTypos(t *testing.T) {
+	index := NewTrigramIndex()
+	for id, name := range []string{"Chicken Breast", "Chickpeas", "Broccoli", "Brown Rice", "Chicken", "Banana"} {
+		index.Add(uint(id+1), name)
+	}
+	ids := func(query string) []uint {
+		got := []uint{}
+		for _, match := range index.Search(query) {
+			got = append(got, match.ID)
+		}
+		return got
+	}
+
+	// The shorter name comes first among the names which start with the query.
+	if got := ids("chicken"); !reflect.DeepEqual(got, []uint{5, 1, 2}) {
+		t.Errorf("Search(chicken) = %v, want [5 1 2]", got)
+	}
+	// Chickpeas starts with the query while Chicken Breast only shares its trigrams.
+	if got := ids("chick"); !reflect.DeepEqual(got, []uint{5, 2, 1}) {
+		t.Errorf("Search(chick) = %v, want [5 2 1]", got)
+	}
+	for _, typo := range []string{"brocoli", "BROCCOLI", "broccolli"} {
+		if got := ids(typo); len(got) == 0 || got[0] != 3 {
+			t.Errorf("Search(%s) = %v, want Broccoli first", typo, got)
+		}
+	}
+	if got := ids("chikcen"); len(got) == 0 || got[0] != 5 {
+		t.Errorf("Search(chikcen) = %v, want Chicken first", got)
+	}
+	if got := ids("rice"); !reflect.DeepEqual(got, []uint{4}) {
+		t.Errorf("Search(rice) = %v, want the second word of Brown Rice", got)
+	}
+	if got := ids("zucchini"); len(got) != 0 {
+		t.Errorf("Search(zucchini) = %v, want no match", got)
+	}
+	if got := ids("  "); len(got) != 0 {
+		t.Errorf("Search() of blanks = %v, want no match", got)
+	}
+}
+
+func TestTrigramIndexScore(t *testing.T) {
+	index := NewTrigramIndex()
+	index.Add(1, "Oatmeal")
+	index.Add(2, "Oat Milk")
+
+	matches := index.Search("oatmeal")
+	if len(matches) == 0 || matches[0].ID != 1 || matches[0].Score != 2 {
+		t.Fatalf("Search(oatmeal) = %v, want ID 1 first with the score 2 of an exact match", matches)
+	}
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Score > matches[i-1].Score {
+			t.Errorf("Search(oatmeal) = %v, want in order of score", matches)
+		}
+	}
+}
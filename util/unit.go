@@ -0,0 +1,110 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// UnitKindMass represents the units of mass which are converted to grams by the factor.
+	UnitKindMass = "mass"
+	// UnitKindVolume represents the units of volume which are converted to milliliters by the factor
+	// and to grams by the density of a food.
+	UnitKindVolume = "volume"
+	// UnitKindCount represents the units which are converted to grams by the size of a food.
+	UnitKindCount = "count"
+)
+
+const (
+	// UnitServing represents the quantity in servings of a food.
+	UnitServing = "serving"
+	// UnitPiece represents the quantity in pieces of a food such as an egg or a slice of bread.
+	UnitPiece = "piece"
+	// DefaultServingSize is the grams of a serving of the food which has no serving size.
+	DefaultServingSize = 100.0
+)
+
+var (
+	// ErrUnknownUnit is returned when a unit is not defined.
+	ErrUnknownUnit = errors.New("unknown unit")
+	// ErrIncompatibleUnit is returned when a unit cannot be converted to grams for a food.
+	ErrIncompatibleUnit = errors.New("incompatible unit")
+)
+
+// Unit defines struct of a unit of measure of a portion.
+type Unit struct {
+	Name   string
+	Kind   string
+	Factor float64
+}
+
+// FoodSizes defines struct of the sizes of a food which convert the volume and the count to grams.
+// Serving and Piece are in grams and Density is in grams per milliliter. Zero means undefined.
+type FoodSizes struct {
+	Serving float64
+	Piece   float64
+	Density float64
+}
+
+// Units are the units of measure which a portion can be entered in.
+var Units = []Unit{
+	{"g", UnitKindMass, 1},
+	{"kg", UnitKindMass, 1000},
+	{"mg", UnitKindMass, 0.001},
+	{"oz", UnitKindMass, 28.349523125},
+	{"lb", UnitKindMass, 453.59237},
+	{"ml", UnitKindVolume, 1},
+	{"l", UnitKindVolume, 1000},
+	{"tsp", UnitKindVolume, 4.92892159375},
+	{"tbsp", UnitKindVolume, 14.78676478125},
+	{"cup", UnitKindVolume, 236.5882365},
+	{"fl_oz", UnitKindVolume, 29.5735295625},
+	{UnitPiece, UnitKindCount, 0},
+	{UnitServing, UnitKindCount, 0},
+}
+
+// FindUnit returns the unit of given name.
+func FindUnit(name string) (Unit, bool) {
+	for _, unit := range Units {
+		if unit.Name == name {
+			return unit, true
+		}
+	}
+	return Unit{}, false
+}
+
+// IsUnit returns true if given name is a defined unit.
+func IsUnit(name string) bool {
+	_, ok := FindUnit(name)
+	return ok
+}
+
+// ToGrams converts given quantity in given unit of a food of given sizes to grams.
+// It returns ErrUnknownUnit if the unit is not defined, and ErrIncompatibleUnit if the food has no density
+// for a unit of volume or no size of a piece. A serving is DefaultServingSize grams if the food has no serving size.
+func ToGrams(quantity float64, name string, sizes FoodSizes) (float64, error) {
+	unit, ok := FindUnit(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownUnit, name)
+	}
+
+	switch unit.Kind {
+	case UnitKindMass:
+		return quantity * unit.Factor, nil
+	case UnitKindVolume:
+		if sizes.Density <= 0 {
+			return 0, fmt.Errorf("%w: %s needs the density", ErrIncompatibleUnit, name)
+		}
+		return quantity * unit.Factor * sizes.Density, nil
+	}
+	if unit.Name == UnitPiece {
+		if sizes.Piece <= 0 {
+			return 0, fmt.Errorf("%w: %s needs the size of a piece", ErrIncompatibleUnit, name)
+		}
+		return quantity * sizes.Piece, nil
+	}
+	if sizes.Serving <= 0 {
+		return quantity * DefaultServingSize, nil
+	}
+	return quantity * sizes.Serving, nil
+}
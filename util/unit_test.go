@@ -0,0 +1,61 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestToGrams(t *testing.T) {
+	cases := []struct {
+		name     string
+		quantity float64
+		unit     string
+		sizes    FoodSizes
+		want     float64
+		err      error
+	}{
+		{"grams", 150, "g", FoodSizes{}, 150, nil},
+		{"kilograms", 1.5, "kg", FoodSizes{}, 1500, nil},
+		{"milligrams", 500, "mg", FoodSizes{}, 0.5, nil},
+		{"ounces", 2, "oz", FoodSizes{}, 56.69904625, nil},
+		{"pounds", 1, "lb", FoodSizes{}, 453.59237, nil},
+		{"milliliters", 200, "ml", FoodSizes{Density: 1.03}, 206, nil},
+		{"liters", 0.5, "l", FoodSizes{Density: 0.92}, 460, nil},
+		{"teaspoons", 2, "tsp", FoodSizes{Density: 1}, 9.8578431875, nil},
+		{"tablespoons", 1, "tbsp", FoodSizes{Density: 0.91}, 13.4559559509375, nil},
+		{"cups", 1, "cup", FoodSizes{Density: 0.5}, 118.29411825, nil},
+		{"fluid ounces", 1, "fl_oz", FoodSizes{Density: 1}, 29.5735295625, nil},
+		{"pieces", 2, UnitPiece, FoodSizes{Piece: 50}, 100, nil},
+		{"servings", 1.5, UnitServing, FoodSizes{Serving: 30}, 45, nil},
+		{"default serving", 2, UnitServing, FoodSizes{}, 2 * DefaultServingSize, nil},
+		{"volume without density", 100, "ml", FoodSizes{Serving: 30, Piece: 50}, 0, ErrIncompatibleUnit},
+		{"piece without size", 1, UnitPiece, FoodSizes{Serving: 30, Density: 1}, 0, ErrIncompatibleUnit},
+		{"unknown unit", 1, "pinch", FoodSizes{Serving: 30, Piece: 50, Density: 1}, 0, ErrUnknownUnit},
+		{"unit is case sensitive", 1, "G", FoodSizes{}, 0, ErrUnknownUnit},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ToGrams(tc.quantity, tc.unit, tc.sizes)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("ToGrams() error = %v, want %v", err, tc.err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("ToGrams() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsUnit(t *testing.T) {
+	for _, unit := range Units {
+		if !IsUnit(unit.Name) {
+			t.Errorf("IsUnit(%q) = false, want true", unit.Name)
+		}
+	}
+	for _, name := range []string{"", "gram", "cups", "Tbsp"} {
+		if IsUnit(name) {
+			t.Errorf("IsUnit(%q) = true, want false", name)
+		}
+	}
+}